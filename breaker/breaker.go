@@ -0,0 +1,200 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+)
+
+// ErrCircuitOpen is returned by wrapped steps while the circuit is open.
+var ErrCircuitOpen = errors.New("breaker: circuit open")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets all requests through, counting failures.
+	Closed State = iota
+	// Open rejects all requests until the cool-down period is over.
+	Open
+	// HalfOpen lets a limited number of probe requests through to test the downstream.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is a circuit breaker for steps.
+// It's safe for concurrent use, and should be shared by all pipelines calling the same downstream.
+type Breaker struct {
+	consecutiveFailures int
+	failureRate         float64
+	minRequests         int
+	coolDown            time.Duration
+	halfOpenRequests    int
+	isFailure           func(error) bool
+	now                 func() time.Time
+
+	mu          sync.Mutex
+	state       State
+	generation  uint64
+	openedAt    time.Time
+	consecutive int
+	inFlight    int
+	probes      int
+	window      *window
+}
+
+// New creates a new Breaker.
+// By default, it opens after 5 consecutive failures, and waits 10 seconds before half-opening.
+// Errors returned once the caller's context is done are not counted, see IsFailure.
+func New(opts ...Option) *Breaker {
+	b := &Breaker{
+		consecutiveFailures: 5,
+		coolDown:            10 * time.Second,
+		halfOpenRequests:    1,
+		now:                 time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// Wrap returns a step running all the given steps through the breaker.
+// Each step is counted as a request, and ErrCircuitOpen is returned without running it while the circuit is open.
+func (b *Breaker) Wrap(steps ...pp.Step) pp.Step {
+	return func(ctx context.Context) error {
+		for _, step := range steps {
+			generation, err := b.allow()
+			if err != nil {
+				return err
+			}
+			err = pp.Execute(ctx, step)
+			switch {
+			case b.isFailure != nil:
+				b.done(generation, b.isFailure(err))
+			case err != nil && ctx.Err() != nil:
+				// The caller gave up, like when a Parallel sibling fails, so it says nothing about the downstream.
+				b.release(generation)
+			default:
+				b.done(generation, err != nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// currentState moves from open to half-open once the cool-down period is over.
+func (b *Breaker) currentState() State {
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.coolDown {
+		b.setState(HalfOpen)
+	}
+	return b.state
+}
+
+func (b *Breaker) setState(state State) {
+	b.state = state
+	b.generation++
+	b.consecutive = 0
+	b.inFlight = 0
+	b.probes = 0
+	if b.window != nil {
+		b.window.reset()
+	}
+	if state == Open {
+		b.openedAt = b.now()
+	}
+}
+
+// allow checks if a request can go through, returning the generation it belongs to.
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.currentState() {
+	case Open:
+		return 0, ErrCircuitOpen
+	case HalfOpen:
+		if b.inFlight >= b.halfOpenRequests {
+			return 0, ErrCircuitOpen
+		}
+	}
+	b.inFlight++
+	return b.generation, nil
+}
+
+// release frees the request, without recording its outcome.
+func (b *Breaker) release(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation {
+		b.inFlight--
+	}
+}
+
+// done records the outcome of a request.
+// Outcomes from previous generations are ignored, since the breaker already changed state.
+func (b *Breaker) done(generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	b.inFlight--
+	switch b.state {
+	case HalfOpen:
+		if failed {
+			b.setState(Open)
+			return
+		}
+		if b.probes++; b.probes >= b.halfOpenRequests {
+			b.setState(Closed)
+		}
+	case Closed:
+		if b.window != nil {
+			b.window.add(b.now(), failed)
+		}
+		if !failed {
+			b.consecutive = 0
+			return
+		}
+		b.consecutive++
+		if b.shouldOpen() {
+			b.setState(Open)
+		}
+	}
+}
+
+func (b *Breaker) shouldOpen() bool {
+	if b.consecutiveFailures > 0 && b.consecutive >= b.consecutiveFailures {
+		return true
+	}
+	if b.window == nil {
+		return false
+	}
+	total, failures := b.window.count(b.now())
+	return total >= b.minRequests && float64(failures)/float64(total) >= b.failureRate
+}
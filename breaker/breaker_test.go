@@ -0,0 +1,134 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClock struct {
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time { return c.now }
+
+func (c *mockClock) Add(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(opts ...Option) (*Breaker, *mockClock) {
+	clock := &mockClock{now: time.Unix(1000, 0)}
+	b := New(opts...)
+	b.now = clock.Now
+	return b, clock
+}
+
+func Test_Breaker(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	fail := func(_ context.Context) error { return errMock }
+	succeed := func(_ context.Context) error { return nil }
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		b, _ := newTestBreaker(ConsecutiveFailures(2))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Closed, b.State())
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Open, b.State())
+		var called bool
+		err := b.Wrap(func(_ context.Context) error {
+			called = true
+			return nil
+		})(ctx)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.False(t, called)
+	})
+	t.Run("half-open closes on success", func(t *testing.T) {
+		b, clock := newTestBreaker(ConsecutiveFailures(1), CoolDown(time.Second))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Open, b.State())
+		clock.Add(time.Second)
+		require.Equal(t, HalfOpen, b.State())
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.Equal(t, Closed, b.State())
+	})
+	t.Run("half-open reopens on failure", func(t *testing.T) {
+		b, clock := newTestBreaker(ConsecutiveFailures(1), CoolDown(time.Second))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		clock.Add(time.Second)
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Open, b.State())
+	})
+	t.Run("half-open limits probes", func(t *testing.T) {
+		b, clock := newTestBreaker(ConsecutiveFailures(1), CoolDown(time.Second), HalfOpenRequests(1))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		clock.Add(time.Second)
+		err := b.Wrap(func(ctx context.Context) error {
+			require.ErrorIs(t, b.Wrap(succeed)(ctx), ErrCircuitOpen)
+			return nil
+		})(ctx)
+		require.NoError(t, err)
+		require.Equal(t, Closed, b.State())
+	})
+	t.Run("failure rate", func(t *testing.T) {
+		b, clock := newTestBreaker(ConsecutiveFailures(0), FailureRate(0.5, time.Minute, 4))
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.Equal(t, Closed, b.State())
+		// Old requests leave the window.
+		clock.Add(time.Minute)
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Closed, b.State())
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		require.Equal(t, Open, b.State())
+	})
+	t.Run("is failure", func(t *testing.T) {
+		b, _ := newTestBreaker(ConsecutiveFailures(1), IsFailure(func(err error) bool {
+			return err != nil && !errors.Is(err, context.Canceled)
+		}))
+		err := b.Wrap(func(_ context.Context) error { return context.Canceled })(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, Closed, b.State())
+	})
+	t.Run("caller cancellation is not a failure", func(t *testing.T) {
+		b, clock := newTestBreaker(ConsecutiveFailures(1))
+		hang := b.Wrap(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, pp.Parallel(0, hang, fail)(ctx), errMock)
+		require.Equal(t, Closed, b.State())
+
+		require.ErrorIs(t, b.Wrap(fail)(ctx), errMock)
+		clock.Add(10 * time.Second)
+		require.Equal(t, HalfOpen, b.State())
+		// A cancelled probe frees its slot, without closing nor opening the circuit.
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		require.ErrorIs(t, hang(cancelled), context.Canceled)
+		require.Equal(t, HalfOpen, b.State())
+		require.NoError(t, b.Wrap(succeed)(ctx))
+		require.Equal(t, Closed, b.State())
+	})
+	t.Run("shared between pipelines", func(t *testing.T) {
+		b := New(ConsecutiveFailures(10))
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = pp.Run(ctx, b.Wrap(fail))
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, Open, b.State())
+	})
+}
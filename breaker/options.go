@@ -0,0 +1,49 @@
+package breaker
+
+import "time"
+
+// Option configures a Breaker.
+type Option func(*Breaker)
+
+// ConsecutiveFailures opens the circuit after n consecutive failures.
+// 0 disables it.
+func ConsecutiveFailures(n int) Option {
+	return func(b *Breaker) {
+		b.consecutiveFailures = n
+	}
+}
+
+// FailureRate opens the circuit when the failure rate over the sliding window reaches rate.
+// The rate is only evaluated after minRequests requests in the window.
+// Example: FailureRate(0.5, time.Minute, 20) opens at 50% failures over the last minute.
+func FailureRate(rate float64, window time.Duration, minRequests int) Option {
+	return func(b *Breaker) {
+		b.failureRate = rate
+		b.minRequests = max(minRequests, 1)
+		b.window = newWindow(window)
+	}
+}
+
+// CoolDown sets how long the circuit stays open before half-opening.
+func CoolDown(d time.Duration) Option {
+	return func(b *Breaker) {
+		b.coolDown = d
+	}
+}
+
+// HalfOpenRequests sets how many probe requests are allowed while half-open.
+// All of them must succeed for the circuit to close.
+func HalfOpenRequests(n int) Option {
+	return func(b *Breaker) {
+		b.halfOpenRequests = max(n, 1)
+	}
+}
+
+// IsFailure sets which errors count as failures.
+// It replaces the default, which counts all errors except the ones returned once the caller's context is done.
+// Example: IsFailure(func(err error) bool { return err != nil && !errors.Is(err, context.Canceled) })
+func IsFailure(isFailure func(error) bool) Option {
+	return func(b *Breaker) {
+		b.isFailure = isFailure
+	}
+}
@@ -0,0 +1,52 @@
+package breaker
+
+import "time"
+
+// windowBuckets is the resolution of the sliding window.
+const windowBuckets = 10
+
+type bucket struct {
+	start    time.Time
+	total    int
+	failures int
+}
+
+// window counts requests and failures over a sliding time window, using buckets.
+type window struct {
+	size    time.Duration
+	buckets [windowBuckets]bucket
+}
+
+func newWindow(size time.Duration) *window {
+	return &window{size: size}
+}
+
+func (w *window) bucketSize() time.Duration {
+	return max(w.size/windowBuckets, 1)
+}
+
+func (w *window) add(now time.Time, failed bool) {
+	start := now.Truncate(w.bucketSize())
+	b := &w.buckets[int(start.UnixNano()/int64(w.bucketSize()))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	b.total++
+	if failed {
+		b.failures++
+	}
+}
+
+func (w *window) count(now time.Time) (total, failures int) {
+	for _, b := range w.buckets {
+		if now.Sub(b.start) < w.size {
+			total += b.total
+			failures += b.failures
+		}
+	}
+	return total, failures
+}
+
+func (w *window) reset() {
+	w.buckets = [windowBuckets]bucket{}
+}
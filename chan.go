@@ -2,6 +2,7 @@ package pp
 
 import (
 	"context"
+	"fmt"
 	"sync"
 )
 
@@ -28,6 +29,8 @@ func ChanDivide[T any](ch *<-chan T, workers ...ChanWorker[T]) Step {
 	return func(ctx context.Context) (err error) {
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel()
+		cfg := getConfig(ctx)
+		path := cfg.metricPath(ctx)
 		for i := range workers {
 			// Spawns 1 routine for each worker, making them consume from job channel.
 			go func(i int) {
@@ -41,7 +44,12 @@ func ChanDivide[T any](ch *<-chan T, workers ...ChanWorker[T]) Step {
 							return
 						}
 						// Execute job and cancel other jobs in case of error.
-						if err := workers[i](ctx, v); err != nil {
+						done := cfg.inFlight(path)
+						err := exec(ctx, fmt.Sprintf("worker[%d]", i), func(ctx context.Context) error {
+							return workers[i](ctx, v)
+						})
+						done()
+						if err != nil {
 							errChan <- err
 							cancel()
 							return
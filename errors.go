@@ -0,0 +1,17 @@
+package pp
+
+import "fmt"
+
+// IndexedError is an error returned by the step at Index.
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexedError) Error() string {
+	return fmt.Sprintf("step %d: %s", e.Index, e.Err)
+}
+
+func (e *IndexedError) Unwrap() error {
+	return e.Err
+}
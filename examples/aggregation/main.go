@@ -33,43 +33,41 @@ type PipelineDependencies struct {
 type Pipeline struct {
 	dep PipelineDependencies
 
-	values []int
-
-	Sum   int
-	AVG   int
-	Count int
-}
-
-func newPipeline(dep PipelineDependencies) Pipeline {
-	return Pipeline{dep: dep}
+	// Futures make the dependencies between steps explicit.
+	values *pp.Future[[]int]
+	sum    *pp.Future[int]
+	count  *pp.Future[int]
+	AVG    *pp.Future[int]
 }
 
-func (s *Pipeline) fetchValues(id string) pp.Step {
-	return func(ctx context.Context) (err error) {
-		s.values, err = s.dep.API.fetchData(ctx, id)
-		return
-	}
+func newPipeline(dep PipelineDependencies, id string) Pipeline {
+	p := Pipeline{dep: dep}
+	p.values = pp.NewFuture(func(ctx context.Context) ([]int, error) {
+		return p.dep.API.fetchData(ctx, id)
+	})
+	p.sum = pp.Then(p.values, calcSum)
+	p.count = pp.Then(p.values, calcCount)
+	p.AVG = pp.Then2(p.sum, p.count, calcAverage)
+	return p
 }
 
-func (s *Pipeline) calcSum(_ context.Context) (err error) {
-	for _, v := range s.values {
-		s.Sum += v
+func calcSum(_ context.Context, values []int) (sum int, err error) {
+	for _, v := range values {
+		sum += v
 	}
 	return
 }
 
-func (s *Pipeline) calcCount(_ context.Context) (err error) {
-	s.Count = len(s.values)
-	return
+func calcCount(_ context.Context, values []int) (int, error) {
+	return len(values), nil
 }
 
-func (s *Pipeline) calcAverage(_ context.Context) (err error) {
+func calcAverage(_ context.Context, sum, count int) (int, error) {
 	// simple example of aggregation error.
-	if s.Count == 0 {
-		return errors.New("cannot calculate average for empty slice")
+	if count == 0 {
+		return 0, errors.New("cannot calculate average for empty slice")
 	}
-	s.AVG = s.Sum / s.Count
-	return
+	return sum / count, nil
 }
 
 func main() {
@@ -77,19 +75,23 @@ func main() {
 	api := API{}
 	pipeline := newPipeline(PipelineDependencies{
 		API: api,
-	})
+	}, "objectID")
 	err := pp.Run(ctx,
-		retry.Constant(retry.Inf, time.Second,
-			pipeline.fetchValues("objectID"),
+		// Steps wait for the futures they depend on, even while their producer is retried.
+		pp.Parallel(4,
+			retry.Constant(retry.Inf, time.Second,
+				pipeline.values.Step,
+			),
+			pipeline.AVG.Step,
+			pipeline.sum.Step,
+			pipeline.count.Step,
 		),
-		pp.Parallel(2,
-			pipeline.calcSum,
-			pipeline.calcCount,
-		),
-		pipeline.calcAverage,
 	)
 	if err != nil {
 		println("could not execute pipeline: ", err.Error())
+		return
 	}
-	// {dep:{API:{}} values:[1 2 3 4 5] Sum:15 AVG:3 Count:5}
+	avg, _ := pipeline.AVG.Get(ctx)
+	println("average: ", avg)
+	// average: 3
 }
@@ -0,0 +1,67 @@
+// Package expvarmetrics implements pp.Metrics using expvar.
+package expvarmetrics
+
+import (
+	"expvar"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+)
+
+// Metrics implements pp.Metrics, storing counters by step path inside an expvar.Map:
+// started, succeeded, failed, duration_seconds, in_flight, queue_wait_seconds and retries.
+type Metrics struct {
+	started          *expvar.Map
+	succeeded        *expvar.Map
+	failed           *expvar.Map
+	durationSeconds  *expvar.Map
+	inFlight         *expvar.Map
+	queueWaitSeconds *expvar.Map
+	retries          *expvar.Map
+}
+
+var _ pp.Metrics = (*Metrics)(nil)
+
+// New creates the metrics inside the given map.
+// Example: expvarmetrics.New(expvar.NewMap("pipego"))
+func New(m *expvar.Map) *Metrics {
+	newMap := func(name string) *expvar.Map {
+		child := new(expvar.Map)
+		m.Set(name, child)
+		return child
+	}
+	return &Metrics{
+		started:          newMap("started"),
+		succeeded:        newMap("succeeded"),
+		failed:           newMap("failed"),
+		durationSeconds:  newMap("duration_seconds"),
+		inFlight:         newMap("in_flight"),
+		queueWaitSeconds: newMap("queue_wait_seconds"),
+		retries:          newMap("retries"),
+	}
+}
+
+func (m *Metrics) StepStarted(path string) {
+	m.started.Add(path, 1)
+}
+
+func (m *Metrics) StepFinished(path string, duration time.Duration, err error) {
+	m.durationSeconds.AddFloat(path, duration.Seconds())
+	if err != nil {
+		m.failed.Add(path, 1)
+		return
+	}
+	m.succeeded.Add(path, 1)
+}
+
+func (m *Metrics) InFlight(path string, delta int) {
+	m.inFlight.Add(path, int64(delta))
+}
+
+func (m *Metrics) QueueWait(path string, wait time.Duration) {
+	m.queueWaitSeconds.AddFloat(path, wait.Seconds())
+}
+
+func (m *Metrics) Retry(path string, _ int) {
+	m.retries.Add(path, 1)
+}
@@ -0,0 +1,48 @@
+package expvarmetrics
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics(t *testing.T) {
+	root := new(expvar.Map)
+	ctx := pp.WithOptions(context.Background(), pp.WithMetrics(New(root)))
+	errMock := errors.New("mock")
+	var attempts int
+
+	err := pp.Run(ctx, pp.Named("checkout", pp.Parallel(1,
+		pp.Named("fetch", func(_ context.Context) error { return nil }),
+		pp.Named("pay", retry.Constant(3, time.Millisecond, pp.Named("call", func(_ context.Context) error {
+			if attempts++; attempts < 3 {
+				return errMock
+			}
+			return nil
+		}))),
+	)))
+	require.NoError(t, err)
+
+	get := func(name, path string) string {
+		v := root.Get(name).(*expvar.Map).Get(path)
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+	require.Equal(t, "1", get("started", "checkout"))
+	require.Equal(t, "1", get("succeeded", "checkout/parallel[*]/fetch"))
+	require.Equal(t, "2", get("failed", "checkout/parallel[*]/pay/call"))
+	require.Equal(t, "1", get("succeeded", "checkout/parallel[*]/pay/call"))
+	require.Equal(t, "1", get("succeeded", "checkout/parallel[*]/pay"))
+	require.Equal(t, "2", get("retries", "checkout/parallel[*]/pay"))
+	require.Equal(t, "0", get("in_flight", "checkout"))
+	require.NotEmpty(t, get("queue_wait_seconds", "checkout"))
+	require.NotEmpty(t, get("duration_seconds", "checkout"))
+}
@@ -0,0 +1,210 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrDependency is returned by steps depending on a Future that failed.
+var ErrDependency = errors.New("dependency failed")
+
+// Future is a value produced by a step, that can be passed as input to later steps.
+// Steps depending on a Future wait until it is resolved, so they can run in parallel with its producer.
+// Waiting steps hold their go-routine, so with a parallelism limit like Parallel(1, dependent, producer)
+// they can take all the slots and never let the producer start: put producers before their dependents,
+// leave room for them in the limit, or use a Graph.
+type Future[T any] struct {
+	fn func(context.Context) (T, error)
+	// run serializes executions of the producer.
+	run  sync.Mutex
+	mu   sync.Mutex
+	done chan struct{}
+	// resolved is set when the result is published, closing done.
+	resolved bool
+	value    T
+	err      error
+}
+
+// NewFuture creates a Future resolved by running fn through its Step.
+func NewFuture[T any](fn func(context.Context) (T, error)) *Future[T] {
+	return &Future[T]{fn: fn, done: make(chan struct{})}
+}
+
+// Value creates an already resolved Future, holding v.
+func Value[T any](v T) *Future[T] {
+	f := NewFuture(func(context.Context) (T, error) { return v, nil })
+	f.value = v
+	f.publish()
+	return f
+}
+
+// Step runs the producer and resolves the Future with its result.
+// Failures inside a retry step are only published when the retry gives up,
+// so the steps waiting on the Future keep waiting while the producer is retried.
+// A failed Future can run again, while a successful one doesn't run anymore.
+func (f *Future[T]) Step(ctx context.Context) error {
+	f.run.Lock()
+	defer f.run.Unlock()
+	if f.succeeded() {
+		return nil
+	}
+	defer func() {
+		// Waiting steps are released with a *PanicError, before the panic goes on to be recovered.
+		if r := recover(); r != nil {
+			f.fail(ctx, &PanicError{Value: r, Stack: debug.Stack()})
+			panic(r)
+		}
+	}()
+	value, err := f.fn(ctx)
+	f.mu.Lock()
+	f.value = value
+	f.mu.Unlock()
+	if err != nil {
+		f.fail(ctx, err)
+		return err
+	}
+	f.mu.Lock()
+	f.err = nil
+	f.mu.Unlock()
+	f.publish()
+	return nil
+}
+
+// fail stores the error, publishing it when the retry step running the producer gives up, if any.
+func (f *Future[T]) fail(ctx context.Context, err error) {
+	f.mu.Lock()
+	f.err = err
+	f.mu.Unlock()
+	if scope, ok := ctx.Value(retryScopeKey{}).(*retryScope); ok {
+		scope.add(f.publish)
+		return
+	}
+	f.publish()
+}
+
+// Get waits for the Future to be resolved, and returns its value.
+// It returns the context cause if the context is done first.
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		var zero T
+		return zero, context.Cause(ctx)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value, f.err
+}
+
+func (f *Future[T]) succeeded() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.resolved && f.err == nil
+}
+
+// publish releases the steps waiting on the Future with its current result.
+func (f *Future[T]) publish() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.resolved {
+		f.resolved = true
+		close(f.done)
+	}
+}
+
+type retryScopeKey struct{}
+
+// retryScope holds the failures of Futures produced inside a retry step, until it gives up.
+type retryScope struct {
+	parent  *retryScope
+	mu      sync.Mutex
+	closed  bool
+	publish []func()
+}
+
+// add holds a failure until the scope is closed, or forwards it if it already is,
+// like for attempts abandoned by a timeout.
+func (s *retryScope) add(publish ...func()) {
+	s.mu.Lock()
+	if !s.closed {
+		s.publish = append(s.publish, publish...)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	// Nested retries leave the failures to the outer one, which may still retry them.
+	if s.parent != nil {
+		s.parent.add(publish...)
+		return
+	}
+	for _, fn := range publish {
+		fn()
+	}
+}
+
+func (s *retryScope) close() {
+	s.mu.Lock()
+	s.closed = true
+	publish := s.publish
+	s.publish = nil
+	s.mu.Unlock()
+	s.add(publish...)
+}
+
+// Retrying marks the steps running with the returned context as being retried.
+// Futures failing inside it only publish their failures when the returned func is called, after the last attempt.
+// It's used by the retry package, and is meant for implementing custom retry steps.
+func Retrying(ctx context.Context) (context.Context, func()) {
+	parent, _ := ctx.Value(retryScopeKey{}).(*retryScope)
+	scope := &retryScope{parent: parent}
+	return context.WithValue(ctx, retryScopeKey{}, scope), scope.close
+}
+
+// dependency waits for the given Future, wrapping its failure with ErrDependency.
+func dependency[T any](ctx context.Context, f *Future[T]) (T, error) {
+	v, err := f.Get(ctx)
+	if err != nil && ctx.Err() == nil {
+		err = fmt.Errorf("%w: %w", ErrDependency, err)
+	}
+	return v, err
+}
+
+// Then creates a Future produced by calling fn with the value of in.
+func Then[T, R any](in *Future[T], fn func(context.Context, T) (R, error)) *Future[R] {
+	return NewFuture(func(ctx context.Context) (r R, err error) {
+		v, err := dependency(ctx, in)
+		if err != nil {
+			return r, err
+		}
+		return fn(ctx, v)
+	})
+}
+
+// Then2 creates a Future produced by calling fn with the values of a and b.
+func Then2[A, B, R any](a *Future[A], b *Future[B], fn func(context.Context, A, B) (R, error)) *Future[R] {
+	return NewFuture(func(ctx context.Context) (r R, err error) {
+		va, err := dependency(ctx, a)
+		if err != nil {
+			return r, err
+		}
+		vb, err := dependency(ctx, b)
+		if err != nil {
+			return r, err
+		}
+		return fn(ctx, va, vb)
+	})
+}
+
+// Consume creates a step calling fn with the value of in.
+func Consume[T any](in *Future[T], fn func(context.Context, T) error) Step {
+	return func(ctx context.Context) error {
+		v, err := dependency(ctx, in)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, v)
+	}
+}
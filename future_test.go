@@ -0,0 +1,133 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Future(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("value", func(t *testing.T) {
+		v, err := pp.Value(1).Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, v)
+	})
+	t.Run("consumers wait for producer", func(t *testing.T) {
+		values := pp.NewFuture(func(_ context.Context) ([]int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return []int{1, 2, 3}, nil
+		})
+		sum := pp.Then(values, func(_ context.Context, values []int) (sum int, _ error) {
+			for _, v := range values {
+				sum += v
+			}
+			return sum, nil
+		})
+		avg := pp.Then2(sum, values, func(_ context.Context, sum int, values []int) (int, error) {
+			return sum / len(values), nil
+		})
+		var got string
+		err := pp.Run(ctx,
+			pp.Parallel(0,
+				pp.Consume(avg, func(_ context.Context, avg int) error {
+					got = strconv.Itoa(avg)
+					return nil
+				}),
+				avg.Step,
+				sum.Step,
+				values.Step,
+			),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "2", got)
+	})
+	t.Run("producer failure", func(t *testing.T) {
+		value := pp.NewFuture(func(_ context.Context) (int, error) {
+			return 0, errMock
+		})
+		var called bool
+		err := pp.RunAll(ctx,
+			value.Step,
+			pp.Consume(value, func(_ context.Context, _ int) error {
+				called = true
+				return nil
+			}),
+		)
+		require.ErrorIs(t, err, pp.ErrDependency)
+		require.ErrorIs(t, err, errMock)
+		require.False(t, called)
+	})
+	t.Run("retry producer", func(t *testing.T) {
+		var calls int
+		value := pp.NewFuture(func(_ context.Context) (int, error) {
+			if calls++; calls < 3 {
+				return 0, errMock
+			}
+			return calls, nil
+		})
+		err := pp.Run(ctx, retry.Constant(3, time.Millisecond, value.Step), value.Step)
+		require.NoError(t, err)
+		v, err := value.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, v)
+		require.Equal(t, 3, calls)
+	})
+	t.Run("dependents wait for retried producer", func(t *testing.T) {
+		var calls int
+		value := pp.NewFuture(func(_ context.Context) (int, error) {
+			if calls++; calls < 3 {
+				return 0, errMock
+			}
+			return calls, nil
+		})
+		var got int
+		err := pp.Run(ctx, pp.Parallel(0,
+			pp.Consume(value, func(_ context.Context, v int) error {
+				got = v
+				return nil
+			}),
+			retry.Constant(3, time.Millisecond, value.Step),
+		))
+		require.NoError(t, err)
+		require.Equal(t, 3, got)
+	})
+	t.Run("retried producer gives up", func(t *testing.T) {
+		value := pp.NewFuture(func(_ context.Context) (int, error) {
+			return 0, errMock
+		})
+		err := pp.ParallelAll(0,
+			pp.Consume(value, func(_ context.Context, _ int) error { return nil }),
+			retry.Retry(2, retry.ConstantDelay(time.Millisecond), retry.Constant(2, time.Millisecond, value.Step)),
+		)(ctx)
+		require.ErrorIs(t, err, pp.ErrDependency)
+		require.ErrorIs(t, err, errMock)
+	})
+	t.Run("producer panic", func(t *testing.T) {
+		value := pp.NewFuture(func(_ context.Context) (int, error) {
+			panic(errMock)
+		})
+		err := pp.ParallelAll(0,
+			value.Step,
+			pp.Consume(value, func(_ context.Context, _ int) error { return nil }),
+		)(ctx)
+		var panicErr *pp.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.ErrorIs(t, err, pp.ErrDependency)
+		_, err = value.Get(ctx)
+		require.ErrorIs(t, err, errMock)
+	})
+	t.Run("context cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := pp.NewFuture(func(_ context.Context) (int, error) { return 0, nil }).Get(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,169 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrDuplicateNode is returned by Graph.Build when two nodes have the same name.
+	ErrDuplicateNode = errors.New("duplicate graph node")
+	// ErrUnknownDependency is returned by Graph.Build when a node depends on a missing node.
+	ErrUnknownDependency = errors.New("unknown graph dependency")
+	// ErrCycle is returned by Graph.Build when the dependencies have a cycle.
+	ErrCycle = errors.New("graph has a cycle")
+)
+
+type graphNode struct {
+	name      string
+	step      Step
+	dependsOn []string
+	// dependents are indexes of the nodes depending on this one.
+	dependents []int
+}
+
+// Graph is a builder for a pipeline of steps with declared dependencies.
+// Each node runs as soon as all its dependencies succeed.
+type Graph struct {
+	nodes []graphNode
+}
+
+// NewGraph creates an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Node adds a named step to the graph, depending on the nodes with the given names.
+// Dependencies can be added before the nodes they refer to.
+func (g *Graph) Node(name string, step Step, dependsOn ...string) *Graph {
+	g.nodes = append(g.nodes, graphNode{name: name, step: Named(name, step), dependsOn: dependsOn})
+	return g
+}
+
+// Build validates the graph and returns a step running it, with at most 'n' nodes at a time.
+// If n is 0, there is no limit.
+// A failed node cancels all its dependents, while independent nodes keep running.
+// The step returns the errors of all failed nodes joined.
+func (g *Graph) Build(n uint16) (Step, error) {
+	nodes := make([]graphNode, len(g.nodes))
+	copy(nodes, g.nodes)
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		if _, ok := index[node.name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateNode, node.name)
+		}
+		index[node.name] = i
+	}
+	deps := make([]int, len(nodes))
+	for i, node := range nodes {
+		for _, dep := range node.dependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, node.name, dep)
+			}
+			nodes[j].dependents = append(nodes[j].dependents, i)
+			deps[i]++
+		}
+	}
+	if err := checkCycle(nodes, deps); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		return runGraph(ctx, n, nodes, deps)
+	}, nil
+}
+
+// checkCycle runs a topological sort of the nodes, failing if some of them are never reached.
+func checkCycle(nodes []graphNode, deps []int) error {
+	pending := make([]int, len(deps))
+	copy(pending, deps)
+	var queue []int
+	for i := range nodes {
+		if pending[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	visited := 0
+	for ; len(queue) > 0; queue = queue[1:] {
+		visited++
+		for _, j := range nodes[queue[0]].dependents {
+			if pending[j]--; pending[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	if visited == len(nodes) {
+		return nil
+	}
+	var cycle []string
+	for i, node := range nodes {
+		if pending[i] > 0 {
+			cycle = append(cycle, node.name)
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrCycle, cycle)
+}
+
+type graphResult struct {
+	node int
+	err  error
+}
+
+// runGraph schedules the nodes from a single go-routine, starting each node once its dependencies succeed.
+func runGraph(ctx context.Context, n uint16, nodes []graphNode, deps []int) error {
+	pending := make([]int, len(deps))
+	copy(pending, deps)
+	// readyAt holds when each node had all its dependencies succeed, to measure its queue wait.
+	readyAt := make([]time.Time, len(nodes))
+	var ready []int
+	for i := range nodes {
+		if pending[i] == 0 {
+			ready, readyAt[i] = append(ready, i), time.Now()
+		}
+	}
+
+	cfg := getConfig(ctx)
+	path := cfg.metricPath(ctx)
+	results := make(chan graphResult)
+	started := make([]bool, len(nodes))
+	var errs []error
+	running := 0
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && (n == 0 || running < int(n)) && ctx.Err() == nil {
+			i := ready[0]
+			ready, started[i] = ready[1:], true
+			running++
+			go func() {
+				cfg.queueWait(path, readyAt[i])
+				defer cfg.inFlight(path)()
+				results <- graphResult{node: i, err: exec(ctx, "", nodes[i].step)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+		result := <-results
+		running--
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+		for _, j := range nodes[result.node].dependents {
+			if pending[j]--; pending[j] == 0 {
+				ready, readyAt[j] = append(ready, j), time.Now()
+			}
+		}
+	}
+	// Nodes that never started were cancelled by a failed dependency, or by the context.
+	for i, node := range nodes {
+		if !started[i] {
+			reportSkipped(ctx, node.step)
+		}
+	}
+	if len(ready) > 0 {
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Graph(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) pp.Step {
+		return func(_ context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	t.Run("dependencies order", func(t *testing.T) {
+		order = nil
+		step, err := pp.NewGraph().
+			Node("total", record("total"), "user", "orders").
+			Node("orders", record("orders"), "user").
+			Node("user", record("user")).
+			Node("prices", record("prices")).
+			Build(0)
+		require.NoError(t, err)
+		require.NoError(t, step(ctx))
+		require.Len(t, order, 4)
+		require.Less(t, slices.Index(order, "user"), slices.Index(order, "orders"))
+		require.Less(t, slices.Index(order, "orders"), slices.Index(order, "total"))
+	})
+	t.Run("runs nodes as soon as dependencies succeed", func(t *testing.T) {
+		order = nil
+		slow := func(_ context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return record("slow")(ctx)
+		}
+		step, err := pp.NewGraph().
+			Node("slow", slow).
+			Node("fast", record("fast")).
+			Node("after-fast", record("after-fast"), "fast").
+			Build(0)
+		require.NoError(t, err)
+		require.NoError(t, step(ctx))
+		require.Equal(t, []string{"fast", "after-fast", "slow"}, order)
+	})
+	t.Run("concurrency limit", func(t *testing.T) {
+		var running, peak atomic.Int32
+		node := func(_ context.Context) error {
+			cur := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+		step, err := pp.NewGraph().
+			Node("a", node).Node("b", node).Node("c", node).Node("d", node, "a").
+			Build(2)
+		require.NoError(t, err)
+		require.NoError(t, step(ctx))
+		require.EqualValues(t, 2, peak.Load())
+	})
+	t.Run("failure cancels dependents", func(t *testing.T) {
+		order = nil
+		step, err := pp.NewGraph().
+			Node("fail", func(_ context.Context) error { return errMock }).
+			Node("dependent", record("dependent"), "fail").
+			Node("transitive", record("transitive"), "dependent").
+			Node("independent", record("independent")).
+			Build(1)
+		require.NoError(t, err)
+		err = step(ctx)
+		require.ErrorIs(t, err, errMock)
+		var stepErr *pp.StepError
+		require.ErrorAs(t, err, &stepErr)
+		require.Equal(t, "fail", stepErr.Path)
+		require.Equal(t, []string{"independent"}, order)
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		step, err := pp.NewGraph().
+			Node("cancel", func(_ context.Context) error { cancel(); return nil }).
+			Node("next", func(_ context.Context) error { return errMock }, "cancel").
+			Build(0)
+		require.NoError(t, err)
+		require.ErrorIs(t, step(ctx), context.Canceled)
+	})
+	t.Run("build errors", func(t *testing.T) {
+		noop := func(_ context.Context) error { return nil }
+		_, err := pp.NewGraph().Node("a", noop).Node("a", noop).Build(0)
+		require.ErrorIs(t, err, pp.ErrDuplicateNode)
+		_, err = pp.NewGraph().Node("a", noop, "b").Build(0)
+		require.ErrorIs(t, err, pp.ErrUnknownDependency)
+		_, err = pp.NewGraph().Node("a", noop, "a").Build(0)
+		require.ErrorIs(t, err, pp.ErrCycle)
+		_, err = pp.NewGraph().
+			Node("a", noop, "c").Node("b", noop, "a").Node("c", noop, "b").Node("d", noop).
+			Build(0)
+		require.ErrorIs(t, err, pp.ErrCycle)
+		require.ErrorContains(t, err, `["a" "b" "c"]`)
+	})
+}
@@ -0,0 +1,65 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Hedge runs the step, and if it has not finished after `delay`, starts another copy of it,
+// up to `maxHedges` extra copies. Negative values are treated as 0.
+// It returns on the first success, cancelling the other copies without waiting for them.
+// A failed copy starts the next one right away, and if all copies fail, their errors are joined.
+func Hedge(delay time.Duration, maxHedges int, step Step) Step {
+	maxHedges = max(maxHedges, 0)
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// The channel is buffered so abandoned copies never block.
+		resultCh := make(chan error, maxHedges+1)
+		var launched int
+		launch := func() {
+			segment := fmt.Sprintf("hedge[%d]", launched)
+			launched++
+			go func() {
+				// Each copy has its own child context.
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				resultCh <- exec(ctx, segment, step)
+			}()
+		}
+		launch()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		var errs []error
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+				if launched <= maxHedges {
+					launch()
+					timer.Reset(delay)
+				}
+			case err := <-resultCh:
+				if err == nil {
+					return nil
+				}
+				errs = append(errs, err)
+				if len(errs) < launched {
+					continue
+				}
+				if launched > maxHedges {
+					return errors.Join(errs...)
+				}
+				// All running copies failed, there is no point in waiting for the delay.
+				launch()
+				timer.Reset(delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Hedge(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("no hedge when fast", func(t *testing.T) {
+		var calls atomic.Int32
+		err := pp.Hedge(time.Second, 2, func(_ context.Context) error {
+			calls.Add(1)
+			return nil
+		})(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, calls.Load())
+	})
+	t.Run("hedges slow step", func(t *testing.T) {
+		var calls atomic.Int32
+		cancelled := make(chan struct{})
+		err := pp.Hedge(10*time.Millisecond, 2, func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				// The first copy hangs until it's cancelled.
+				<-ctx.Done()
+				close(cancelled)
+				return ctx.Err()
+			}
+			return nil
+		})(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, calls.Load())
+		<-cancelled
+	})
+	t.Run("failure starts next copy", func(t *testing.T) {
+		var calls atomic.Int32
+		t1 := time.Now()
+		err := pp.Hedge(time.Hour, 2, func(_ context.Context) error {
+			if calls.Add(1) < 3 {
+				return errMock
+			}
+			return nil
+		})(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, calls.Load())
+		require.Less(t, time.Since(t1), time.Second)
+	})
+	t.Run("all fail", func(t *testing.T) {
+		var calls atomic.Int32
+		err := pp.Hedge(time.Millisecond, 1, func(_ context.Context) error {
+			calls.Add(1)
+			return errMock
+		})(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.EqualValues(t, 2, calls.Load())
+	})
+	t.Run("negative hedges", func(t *testing.T) {
+		var calls atomic.Int32
+		err := pp.Hedge(time.Millisecond, -5, func(_ context.Context) error {
+			calls.Add(1)
+			return errMock
+		})(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.EqualValues(t, 1, calls.Load())
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := pp.Hedge(time.Millisecond, 1, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -9,6 +9,7 @@ type key int
 
 var SectionKey = key(0)
 var AutomaticSectionKey = key(1)
+var ConfigKey = key(2)
 
 func GetFunctionName(i interface{}) string {
 	return runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name()
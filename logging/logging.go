@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+)
+
+type loggerKey struct{}
+
+type config struct {
+	successLevel slog.Level
+	failureLevel slog.Level
+}
+
+// Option configures the logging middleware.
+type Option func(*config)
+
+// SuccessLevel sets the level for step start and finish logs, debug by default.
+func SuccessLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.successLevel = level
+	}
+}
+
+// FailureLevel sets the level for failed steps logs, error by default.
+func FailureLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.failureLevel = level
+	}
+}
+
+// Middleware returns a middleware logging start, finish, duration, errors and retry attempts of steps.
+// Failures are logged at the failure level only by the step where they started,
+// the steps they propagate through log them at the success level.
+// It also stores the logger in the step context, see FromContext.
+// Nothing is computed, nor stored, when the handler is disabled for the configured levels.
+// Example: pp.WithOptions(ctx, pp.WithMiddleware(logging.Middleware(slog.Default())))
+func Middleware(logger *slog.Logger, opts ...Option) pp.Middleware {
+	cfg := config{
+		successLevel: slog.LevelDebug,
+		failureLevel: slog.LevelError,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(step pp.Step) pp.Step {
+		return func(ctx context.Context) error {
+			success := logger.Enabled(ctx, cfg.successLevel)
+			if !success && !logger.Enabled(ctx, cfg.failureLevel) {
+				return step(ctx)
+			}
+			parent, _ := ctx.Value(loggedKey{}).(*logged)
+			children := &logged{}
+			ctx = context.WithValue(withLogger(ctx, logger), loggedKey{}, children)
+
+			attrs := stepAttrs(ctx)
+			if success {
+				logger.LogAttrs(ctx, cfg.successLevel, "step started", attrs...)
+			}
+			start := time.Now()
+			err := step(ctx)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			switch {
+			case err != nil:
+				level := cfg.failureLevel
+				if children.contains(err) {
+					level = cfg.successLevel
+				}
+				logger.LogAttrs(ctx, level, "step failed", append(attrs, slog.Any("error", err))...)
+				if parent != nil {
+					parent.add(err)
+				}
+			case success:
+				logger.LogAttrs(ctx, cfg.successLevel, "step finished", attrs...)
+			}
+			return err
+		}
+	}
+}
+
+type loggedKey struct{}
+
+// logged holds the failures already logged by the child steps, so their parents don't log them again.
+type logged struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (l *logged) add(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, err)
+}
+
+func (l *logged) contains(err error) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, logged := range l.errs {
+		if errors.Is(err, logged) {
+			return true
+		}
+	}
+	return false
+}
+
+// withLogger stores the logger in the context, unless its parent step already did it.
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	if current, _ := ctx.Value(loggerKey{}).(*slog.Logger); current == logger {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored by the middleware, enriched with the step path.
+// It returns slog.Default() outside of the middleware.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	if !ok {
+		return slog.Default()
+	}
+	return slog.New(logger.Handler().WithAttrs(stepAttrs(ctx)))
+}
+
+func stepAttrs(ctx context.Context) []slog.Attr {
+	path := pp.Path(ctx)
+	attrs := []slog.Attr{
+		slog.String("step", path[strings.LastIndex(path, "/")+1:]),
+		slog.String("path", path),
+	}
+	if attempt := retry.Attempt(ctx); attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", attempt))
+	}
+	return attrs
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var logs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		logs = append(logs, entry)
+	}
+	return logs
+}
+
+func Test_Middleware(t *testing.T) {
+	errMock := errors.New("mock")
+	t.Run("logs steps", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(Middleware(logger)))
+		var attempts int
+		err := pp.Run(ctx, retry.Constant(2, time.Millisecond, pp.Named("fetch-user", func(ctx context.Context) error {
+			FromContext(ctx).Info("fetching")
+			if attempts++; attempts == 1 {
+				return errMock
+			}
+			return nil
+		})))
+		require.NoError(t, err)
+
+		logs := parseLogs(t, &buf)
+		var msgs []string
+		for _, entry := range logs {
+			if entry["path"] != "fetch-user" {
+				continue
+			}
+			msgs = append(msgs, entry["msg"].(string))
+			require.Equal(t, "fetch-user", entry["step"])
+			require.NotNil(t, entry["attempt"])
+		}
+		require.Equal(t, []string{
+			"step started", "fetching", "step failed",
+			"step started", "fetching", "step finished",
+		}, msgs)
+	})
+	t.Run("quiet by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(Middleware(logger)))
+		err := pp.Run(ctx,
+			pp.Named("ok", func(_ context.Context) error { return nil }),
+			pp.Named("fail", func(_ context.Context) error { return errMock }),
+		)
+		require.ErrorIs(t, err, errMock)
+		logs := parseLogs(t, &buf)
+		require.Len(t, logs, 1)
+		require.Equal(t, "step failed", logs[0]["msg"])
+		require.Equal(t, "fail", logs[0]["path"])
+		require.Equal(t, "mock", logs[0]["error"])
+		require.Equal(t, "ERROR", logs[0]["level"])
+	})
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+		ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(Middleware(logger, FailureLevel(slog.LevelWarn))))
+		err := pp.Run(ctx, func(ctx context.Context) error {
+			require.Equal(t, slog.Default(), FromContext(ctx))
+			return errMock
+		})
+		require.ErrorIs(t, err, errMock)
+		require.Empty(t, buf.String())
+	})
+	t.Run("failure logged once", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(Middleware(logger)))
+		err := pp.Run(ctx, pp.Named("checkout", pp.Steps{
+			pp.Named("stage", pp.Parallel(2, func(_ context.Context) error { return errMock })),
+		}.Group()))
+		require.ErrorIs(t, err, errMock)
+
+		levels := map[string]string{}
+		for _, entry := range parseLogs(t, &buf) {
+			if entry["msg"] == "step failed" {
+				levels[entry["path"].(string)] = entry["level"].(string)
+			}
+		}
+		require.Equal(t, map[string]string{
+			"checkout/stage/parallel[0]": "ERROR",
+			"checkout/stage":             "DEBUG",
+			"checkout":                   "DEBUG",
+		}, levels)
+	})
+	t.Run("failure logged once without paths", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(Middleware(logger)))
+		err := pp.Run(ctx, pp.Steps{func(_ context.Context) error { return errMock }}.Group())
+		require.ErrorIs(t, err, errMock)
+		require.Len(t, parseLogs(t, &buf), 1)
+
+		buf.Reset()
+		err = pp.Run(ctx, pp.Steps{func(_ context.Context) error { panic(errMock) }}.Group())
+		require.ErrorIs(t, err, errMock)
+		logs := parseLogs(t, &buf)
+		require.Len(t, logs, 1)
+		require.Equal(t, "ERROR", logs[0]["level"])
+	})
+	t.Run("no allocations when disabled", func(t *testing.T) {
+		logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+		step := Middleware(logger)(func(_ context.Context) error { return nil })
+		ctx := context.Background()
+		require.Zero(t, testing.AllocsPerRun(100, func() { _ = step(ctx) }))
+	})
+	t.Run("outside middleware", func(t *testing.T) {
+		require.Equal(t, slog.Default(), FromContext(context.Background()))
+	})
+}
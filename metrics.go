@@ -0,0 +1,84 @@
+package pp
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// Metrics receives events about step executions.
+// Paths are the ones returned by Path, see Named, with branch indexes collapsed,
+// like "checkout/parallel[*]/fetch-user", so they are bounded and can be used as metric labels.
+type Metrics interface {
+	// StepStarted is called when a step starts.
+	StepStarted(path string)
+	// StepFinished is called when a step finishes, with its duration and outcome.
+	StepFinished(path string, duration time.Duration, err error)
+	// InFlight is called with +1 when a Parallel branch or ChanDivide job starts, and -1 when it finishes.
+	// The path is the one of the Parallel or ChanDivide step.
+	InFlight(path string, delta int)
+	// QueueWait is called with the time a Parallel branch waited for the parallelism limit.
+	QueueWait(path string, wait time.Duration)
+	// Retry is called before each retry, attempt being the attempt that failed, starting from 1.
+	Retry(path string, attempt int)
+}
+
+// WithMetrics reports metrics for every step executed under the context.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// NotifyRetry tells pipego the step running with ctx is being retried, for metrics and reports.
+// It's used by the retry package, and is meant for implementing custom retry steps.
+func NotifyRetry(ctx context.Context, attempt int) {
+	reportRetry(ctx, attempt)
+	if m := getConfig(ctx).metrics; m != nil {
+		m.Retry(metricPath(ctx), attempt)
+	}
+}
+
+// branchIndex matches the index of branch segments, like "parallel[2]".
+var branchIndex = regexp.MustCompile(`\[\d+\]`)
+
+// metricPath returns the path of the step running with ctx, with branch indexes collapsed.
+// Branches of the same step are reported together, since their number is unbounded.
+func metricPath(ctx context.Context) string {
+	return branchIndex.ReplaceAllLiteralString(Path(ctx), "[*]")
+}
+
+// metricPath returns the metric path of the step running with ctx, or "" without metrics, where it's not used.
+func (c config) metricPath(ctx context.Context) string {
+	if c.metrics == nil {
+		return ""
+	}
+	return metricPath(ctx)
+}
+
+// measured reports start and finish of each step execution.
+func measured(m Metrics, step Step) Step {
+	return func(ctx context.Context) (err error) {
+		path := metricPath(ctx)
+		m.StepStarted(path)
+		start := time.Now()
+		defer func() { m.StepFinished(path, time.Since(start), err) }()
+		return step(ctx)
+	}
+}
+
+// inFlight reports a branch started at path, returning a func to report it finished.
+func (c config) inFlight(path string) func() {
+	if c.metrics == nil {
+		return func() {}
+	}
+	c.metrics.InFlight(path, 1)
+	return func() { c.metrics.InFlight(path, -1) }
+}
+
+// queueWait reports how long a branch waited to start.
+func (c config) queueWait(path string, queued time.Time) {
+	if c.metrics != nil {
+		c.metrics.QueueWait(path, time.Since(queued))
+	}
+}
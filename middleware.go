@@ -0,0 +1,68 @@
+package pp
+
+import "context"
+
+// Middleware decorates a step, like logging, metrics or tracing.
+type Middleware func(Step) Step
+
+// Chain composes all the given middlewares into one, the first one being the outermost.
+func Chain(mws ...Middleware) Middleware {
+	return func(step Step) Step {
+		for i := len(mws) - 1; i >= 0; i-- {
+			step = mws[i](step)
+		}
+		return step
+	}
+}
+
+// Apply decorates all the given steps with the middleware.
+func (m Middleware) Apply(steps ...Step) (out Steps) {
+	out = make(Steps, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, m(step))
+	}
+	return
+}
+
+// WithMiddleware installs middlewares for every step executed under the context,
+// including the ones nested inside groups, parallel sections and other combinators.
+// Example: pp.Run(pp.WithOptions(ctx, pp.WithMiddleware(logging)), steps...)
+func WithMiddleware(mws ...Middleware) Option {
+	return func(c *config) {
+		c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], mws...)
+	}
+}
+
+// Execute runs a child step the same way pipego's combinators do,
+// automatically naming it, recovering panics and applying the context's middlewares.
+// It's meant for implementing custom combinators.
+func Execute(ctx context.Context, step Step) error {
+	return exec(ctx, "", step)
+}
+
+// intercept applies the context's tracer, metrics, middlewares and report to the step.
+// Named steps are left as they are, since they intercept their inner step after adding their name to the path.
+func intercept(ctx context.Context, step Step) Step {
+	cfg := getConfig(ctx)
+	parent, reporting := ctx.Value(reportKey{}).(*Report)
+	if isNamed(step) || len(cfg.middleware) == 0 && cfg.metrics == nil && !reporting && cfg.tracer == nil {
+		return step
+	}
+	// Panics are recovered right around the step, so the interceptors see them as errors.
+	inner := step
+	step = func(ctx context.Context) error { return call(ctx, inner) }
+	if len(cfg.middleware) > 0 {
+		step = Chain(cfg.middleware...)(step)
+	}
+	if cfg.metrics != nil {
+		step = measured(cfg.metrics, step)
+	}
+	if reporting {
+		step = reported(parent, step)
+	}
+	// The tracer is the outermost, so middlewares see the step span.
+	if cfg.tracer != nil {
+		step = traced(cfg.tracer, step)
+	}
+	return step
+}
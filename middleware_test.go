@@ -0,0 +1,75 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Chain(t *testing.T) {
+	var calls []string
+	mw := func(name string) pp.Middleware {
+		return func(step pp.Step) pp.Step {
+			return func(ctx context.Context) error {
+				calls = append(calls, name)
+				return step(ctx)
+			}
+		}
+	}
+	steps := pp.Chain(mw("a"), mw("b")).Apply(func(_ context.Context) error {
+		calls = append(calls, "step")
+		return nil
+	})
+	require.Len(t, steps, 1)
+	require.NoError(t, steps[0](context.Background()))
+	require.Equal(t, []string{"a", "b", "step"}, calls)
+}
+
+func Test_WithMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	recordPath := func(step pp.Step) pp.Step {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			paths = append(paths, pp.Path(ctx))
+			mu.Unlock()
+			return step(ctx)
+		}
+	}
+	wrapErr := pp.ErrorWrapper(func(err error) error {
+		return fmt.Errorf("wrapped: %w", err)
+	}).Middleware()
+	ctx := pp.WithOptions(context.Background(), pp.WithMiddleware(recordPath), pp.WithMiddleware(wrapErr))
+	errMock := errors.New("mock")
+	var attempts int
+
+	err := pp.Run(ctx,
+		pp.Named("checkout", pp.Parallel(2,
+			func(_ context.Context) error { return nil },
+			pp.Named("fetch-user", func(_ context.Context) error { return nil }),
+		)),
+		retry.Constant(2, time.Millisecond, pp.Named("pay", func(_ context.Context) error {
+			attempts++
+			return errMock
+		})),
+	)
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, 2, attempts)
+	require.ElementsMatch(t, []string{
+		"checkout",
+		"checkout/parallel[0]",
+		"checkout/parallel[1]/fetch-user",
+		"",
+		"pay",
+		"pay",
+	}, paths)
+	// Each step wraps the error once: pay, the retry step.
+	require.Equal(t, "wrapped: retry: maximum attempts reached: pay: wrapped: mock", err.Error())
+}
@@ -0,0 +1,170 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sonalys/pipego/internal"
+)
+
+// StepError is returned by named steps, telling where in the pipeline the error happened.
+type StepError struct {
+	// Path is the path of the failed step, like "checkout/parallel[2]/fetch-user".
+	Path string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+type namedStep struct {
+	name string
+	step Step
+}
+
+// namedPC identifies steps created by Named, since all of them share the same method value wrapper.
+var namedPC uintptr
+
+func init() {
+	namedPC = reflect.ValueOf(namedStep{}.run).Pointer()
+}
+
+func isNamed(step Step) bool {
+	return step != nil && reflect.ValueOf(step).Pointer() == namedPC
+}
+
+// describeCtx is used to get the name of a named step without running it.
+type describeCtx struct {
+	context.Context
+	name string
+}
+
+func (s namedStep) run(ctx context.Context) error {
+	if d, ok := ctx.(*describeCtx); ok {
+		d.name = s.name
+		return nil
+	}
+	ctx = withSegment(ctx, s.name)
+	return wrapStepError(ctx, call(ctx, intercept(ctx, s.step)))
+}
+
+// wrapStepError wraps err in a *StepError with the path of the step running with ctx.
+// Only the innermost step wraps the error, since it has the most precise path.
+func wrapStepError(ctx context.Context, err error) error {
+	var stepErr *StepError
+	if err == nil || errors.As(err, &stepErr) {
+		return err
+	}
+	path := Path(ctx)
+	if path == "" {
+		return err
+	}
+	return &StepError{Path: path, Err: err}
+}
+
+// Named gives a name to the step, used in its path.
+// Steps without a name are automatically named after their function.
+// Errors are wrapped in a *StepError by the innermost step with a path, pointing at the step that failed.
+// Example: pp.Named("fetch-user", fetchUser)
+func Named(name string, step Step) Step {
+	return namedStep{name, step}.run
+}
+
+// Path returns the path of the step running with ctx, like "checkout/parallel[2]/fetch-user".
+func Path(ctx context.Context) string {
+	path, _ := ctx.Value(internal.SectionKey).([]string)
+	return strings.Join(path, "/")
+}
+
+// stepName returns the last segment of the step path.
+func stepName(ctx context.Context) string {
+	path, _ := ctx.Value(internal.SectionKey).([]string)
+	if len(path) == 0 {
+		return ""
+	}
+	return path[len(path)-1]
+}
+
+func withSegment(ctx context.Context, segment string) context.Context {
+	path, _ := ctx.Value(internal.SectionKey).([]string)
+	// Clip forces a copy, so sibling steps never share the same backing array.
+	return context.WithValue(ctx, internal.SectionKey, append(slices.Clip(path), segment))
+}
+
+// anonymous matches the suffix of function literals, like "main.main.func1.2".
+var anonymous = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// autoNames caches the automatic names by function pointer, since they are resolved on every step execution.
+var autoNames = struct {
+	sync.RWMutex
+	m map[uintptr]string
+}{m: map[uintptr]string{}}
+
+// autoName returns the name of the step's function, without its package.
+// Steps from pipego, including named steps, are not named since they are only structural,
+// and neither are function literals.
+func autoName(step Step) string {
+	pc := reflect.ValueOf(step).Pointer()
+	autoNames.RLock()
+	name, ok := autoNames.m[pc]
+	autoNames.RUnlock()
+	if ok {
+		return name
+	}
+	name = resolveName(step)
+	autoNames.Lock()
+	autoNames.m[pc] = name
+	autoNames.Unlock()
+	return name
+}
+
+func resolveName(step Step) string {
+	name := internal.GetFunctionName(step)
+	if strings.HasPrefix(name, "github.com/sonalys/pipego.") || strings.HasPrefix(name, "github.com/sonalys/pipego/") {
+		return ""
+	}
+	if anonymous.MatchString(name) {
+		return ""
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// nameOf returns the name a step would have in its path, without running it.
+func nameOf(step Step) string {
+	if !isNamed(step) {
+		return autoName(step)
+	}
+	d := &describeCtx{Context: context.Background()}
+	_ = step(d)
+	return d.name
+}
+
+// exec runs a child step, adding the segment and the step's automatic name to its path.
+// Its errors are wrapped in a *StepError, unless they already are.
+func exec(ctx context.Context, segment string, step Step) error {
+	if segment != "" {
+		ctx = withSegment(ctx, segment)
+	}
+	if name := autoName(step); name != "" {
+		ctx = withSegment(ctx, name)
+	}
+	// intercept recovers the step itself, call only catches panics from the middlewares.
+	return wrapStepError(ctx, call(ctx, intercept(ctx, step)))
+}
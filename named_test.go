@@ -0,0 +1,59 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func fetchUser(ctx context.Context) error {
+	return errors.New(pp.Path(ctx))
+}
+
+type service struct{}
+
+func (service) fetchOrder(ctx context.Context) error {
+	return errors.New(pp.Path(ctx))
+}
+
+func Test_Named(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("step path", func(t *testing.T) {
+		err := pp.Run(ctx,
+			pp.Named("checkout", pp.Parallel(2,
+				func(_ context.Context) error { return nil },
+				pp.Named("fetch-user", func(_ context.Context) error { return errMock }),
+			)),
+		)
+		var stepErr *pp.StepError
+		require.ErrorAs(t, err, &stepErr)
+		require.Equal(t, "checkout/parallel[1]/fetch-user", stepErr.Path)
+		require.ErrorIs(t, err, errMock)
+		require.Equal(t, "checkout/parallel[1]/fetch-user: mock", err.Error())
+	})
+	t.Run("automatic names", func(t *testing.T) {
+		err := pp.Run(ctx, pp.Named("checkout", pp.Steps{fetchUser}.Group()))
+		require.Equal(t, "checkout/fetchUser: checkout/fetchUser", err.Error())
+		err = pp.Run(ctx, pp.Named("checkout", pp.Steps{service{}.fetchOrder}.Group()))
+		require.Equal(t, "checkout/service.fetchOrder: checkout/service.fetchOrder", err.Error())
+	})
+	t.Run("innermost segment", func(t *testing.T) {
+		err := pp.Run(ctx, pp.Named("checkout", pp.Steps{pp.Named("stage", pp.Parallel(2, fetchUser))}.Group()))
+		var stepErr *pp.StepError
+		require.ErrorAs(t, err, &stepErr)
+		require.Equal(t, "checkout/stage/parallel[0]/fetchUser", stepErr.Path)
+
+		err = pp.Parallel(2, func(_ context.Context) error { return errMock })(ctx)
+		require.ErrorAs(t, err, &stepErr)
+		require.Equal(t, "parallel[0]", stepErr.Path)
+		require.ErrorIs(t, err, errMock)
+	})
+	t.Run("no name", func(t *testing.T) {
+		err := pp.Run(ctx, func(_ context.Context) error { return errMock })
+		require.Equal(t, errMock, err)
+	})
+}
@@ -0,0 +1,41 @@
+package pp
+
+import (
+	"context"
+
+	"github.com/sonalys/pipego/internal"
+)
+
+type config struct {
+	repanic    bool
+	middleware []Middleware
+	tracer     Tracer
+	metrics    Metrics
+}
+
+// Option configures the behavior of all steps executed under a context.
+type Option func(*config)
+
+// WithOptions returns a copy of ctx configured with the given options,
+// they apply to all steps executed with the returned context.
+// Example: pp.Run(pp.WithOptions(ctx, pp.Repanic()), steps...)
+func WithOptions(ctx context.Context, opts ...Option) context.Context {
+	cfg := getConfig(ctx)
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return context.WithValue(ctx, internal.ConfigKey, cfg)
+}
+
+func getConfig(ctx context.Context) config {
+	cfg, _ := ctx.Value(internal.ConfigKey).(config)
+	return cfg
+}
+
+// Repanic makes steps re-panic instead of returning a *PanicError,
+// crashing the process when they panic inside go-routines owned by pipego.
+func Repanic() Option {
+	return func(c *config) {
+		c.repanic = true
+	}
+}
@@ -0,0 +1,47 @@
+// Package otel adapts OpenTelemetry tracers to pipego.
+// It's a separate module, so pipego itself doesn't depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer adapts an OpenTelemetry tracer to pipego.
+// Spans have the step path and the retry attempt as attributes.
+// Example: pp.WithOptions(ctx, pp.WithTracer(otel.NewTracer(otel.Tracer("pipeline"))))
+func NewTracer(t trace.Tracer) pp.Tracer {
+	return tracer{t}
+}
+
+func (t tracer) Start(ctx context.Context, name string) (context.Context, pp.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("pipego.path", pp.Path(ctx)),
+	}
+	if attempt := retry.Attempt(ctx); attempt > 0 {
+		attrs = append(attrs, attribute.Int("pipego.attempt", attempt))
+	}
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, span{s}
+}
+
+type span struct {
+	trace.Span
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+	s.Span.End()
+}
@@ -0,0 +1,37 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_NewTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx := pp.WithOptions(context.Background(), pp.WithTracer(NewTracer(provider.Tracer("test"))))
+	errMock := errors.New("mock")
+
+	err := pp.Run(ctx, pp.Named("checkout", pp.Parallel(1,
+		pp.Named("fetch-user", func(_ context.Context) error { return errMock }),
+	)))
+	require.ErrorIs(t, err, errMock)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	// Spans are exported when they end, so the child comes first.
+	child, parent := spans[0], spans[1]
+	require.Equal(t, "fetch-user", child.Name)
+	require.Equal(t, "checkout", parent.Name)
+	require.Equal(t, parent.SpanContext.SpanID(), child.Parent.SpanID())
+	require.Contains(t, child.Attributes, attribute.String("pipego.path", "checkout/parallel[0]/fetch-user"))
+	require.Equal(t, codes.Error, child.Status.Code)
+	require.Equal(t, "mock", child.Status.Description)
+}
@@ -2,28 +2,64 @@ package pp
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
 
 // Parallel runs all the given steps in parallel,
 // It cancels context for the first non-nil error and returns.
-// It runs 'n' go-routines at a time.
+// It runs 'n' go-routines at a time. If n is 0, there is no limit.
 func Parallel(n uint16, steps ...Step) Step {
 	return func(ctx context.Context) (err error) {
-		if n <= 0 {
-			n = uint16(len(steps))
-		}
-
+		cfg := getConfig(ctx)
+		path := cfg.metricPath(ctx)
 		errgrp, ctx := errgroup.WithContext(ctx)
-		errgrp.SetLimit(int(n))
+		if n > 0 {
+			errgrp.SetLimit(int(n))
+		}
 
-		for _, step := range steps {
+		for i, step := range steps {
+			queued := time.Now()
 			errgrp.Go(func() error {
-				return step(ctx)
+				cfg.queueWait(path, queued)
+				defer cfg.inFlight(path)()
+				return exec(ctx, fmt.Sprintf("parallel[%d]", i), step)
 			})
 		}
 
 		return errgrp.Wait()
 	}
 }
+
+// ParallelAll runs all the given steps in parallel, even if some of them fail.
+// It returns all errors joined, each one as an *IndexedError.
+// It runs 'n' go-routines at a time. If n is 0, there is no limit.
+func ParallelAll(n uint16, steps ...Step) Step {
+	return func(ctx context.Context) (err error) {
+		cfg := getConfig(ctx)
+		path := cfg.metricPath(ctx)
+		var errgrp errgroup.Group
+		if n > 0 {
+			errgrp.SetLimit(int(n))
+		}
+
+		errs := make([]error, len(steps))
+		for i, step := range steps {
+			queued := time.Now()
+			errgrp.Go(func() error {
+				cfg.queueWait(path, queued)
+				defer cfg.inFlight(path)()
+				if err := exec(ctx, fmt.Sprintf("parallel[%d]", i), step); err != nil {
+					errs[i] = &IndexedError{Index: i, Err: err}
+				}
+				return nil
+			})
+		}
+		errgrp.Wait()
+
+		return errors.Join(errs...)
+	}
+}
@@ -2,7 +2,9 @@ package pp_test
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	pp "github.com/sonalys/pipego"
@@ -23,25 +25,28 @@ func Test_Parallel(t *testing.T) {
 			a, b int
 		}
 		var s state
-		err := pp.Parallel(2,
-			func(_ context.Context) (err error) {
-				s.a = 1
-				ready.Done()
-				wg.Wait()
-				return nil
-			},
-			func(_ context.Context) (err error) {
-				s.b = 2
-				ready.Done()
-				wg.Wait()
-				return nil
-			},
-		)(ctx)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- pp.Parallel(2,
+				func(_ context.Context) (err error) {
+					s.a = 1
+					ready.Done()
+					wg.Wait()
+					return nil
+				},
+				func(_ context.Context) (err error) {
+					s.b = 2
+					ready.Done()
+					wg.Wait()
+					return nil
+				},
+			)(ctx)
+		}()
 		ready.Wait()
-		require.NoError(t, err)
+		wg.Done()
+		require.NoError(t, <-errCh)
 		require.Equal(t, 1, s.a)
 		require.Equal(t, 2, s.b)
-		wg.Done()
 	})
 	t.Run("runs at the specified parallelism number", func(t *testing.T) {
 		var wg, ready sync.WaitGroup
@@ -51,8 +56,9 @@ func Test_Parallel(t *testing.T) {
 			a, b int
 		}
 		var s state
-		go require.NotPanics(t, func() {
-			err := pp.Parallel(1,
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- pp.Parallel(1,
 				func(_ context.Context) (err error) {
 					s.a = 1
 					ready.Done()
@@ -66,11 +72,44 @@ func Test_Parallel(t *testing.T) {
 					return nil
 				},
 			)(ctx)
-			require.NoError(t, err)
-		})
+		}()
 		ready.Wait()
 		require.NotEqual(t, s.b, s.a)
 		ready.Add(1)
 		wg.Done()
+		require.NoError(t, <-errCh)
+	})
+}
+
+func Test_ParallelAll(t *testing.T) {
+	ctx := context.Background()
+	t.Run("empty", func(t *testing.T) {
+		err := pp.ParallelAll(5)(ctx)
+		require.NoError(t, err)
+	})
+	t.Run("runs all steps", func(t *testing.T) {
+		errA, errB := errors.New("a"), errors.New("b")
+		var ran atomic.Int32
+		err := pp.Steps{
+			func(_ context.Context) error {
+				ran.Add(1)
+				return errA
+			},
+			func(_ context.Context) error {
+				ran.Add(1)
+				return nil
+			},
+			func(_ context.Context) error {
+				ran.Add(1)
+				return errB
+			},
+		}.ParallelAll(1)(ctx)
+		require.EqualValues(t, 3, ran.Load())
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+		require.Equal(t, errors.Join(
+			&pp.IndexedError{Index: 0, Err: &pp.StepError{Path: "parallel[0]", Err: errA}},
+			&pp.IndexedError{Index: 2, Err: &pp.StepError{Path: "parallel[2]", Err: errB}},
+		), err)
 	})
 }
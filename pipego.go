@@ -2,6 +2,7 @@ package pp
 
 import (
 	"context"
+	"errors"
 )
 
 type (
@@ -20,13 +21,37 @@ func Run(ctx context.Context, steps ...Step) error {
 	return runSteps(ctx, steps...)
 }
 
+// RunAll receives a context, and runs all pipeline functions, even if some of them fail.
+// It returns all errors joined, each one as an *IndexedError.
+// It stops if the context is done.
+func RunAll(ctx context.Context, steps ...Step) error {
+	return runAllSteps(ctx, steps...)
+}
+
+func runAllSteps(ctx context.Context, steps ...Step) error {
+	var errs []error
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			reportSkipped(ctx, steps[i:]...)
+			break
+		}
+		if err := exec(ctx, "", step); err != nil {
+			errs = append(errs, &IndexedError{Index: i, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func runSteps(ctx context.Context, steps ...Step) error {
 	var err error
-	for _, step := range steps {
+	for i, step := range steps {
 		if err = ctx.Err(); err != nil {
+			reportSkipped(ctx, steps[i:]...)
 			return err
 		}
-		if err = step(ctx); err != nil {
+		if err = exec(ctx, "", step); err != nil {
+			reportSkipped(ctx, steps[i+1:]...)
 			return err
 		}
 	}
@@ -39,6 +64,16 @@ func (s Steps) Group() func(context.Context) error {
 	}
 }
 
+func (s Steps) GroupAll() Step {
+	return func(ctx context.Context) (err error) {
+		return runAllSteps(ctx, s...)
+	}
+}
+
 func (s Steps) Parallel(n uint16) Step {
 	return Parallel(n, s...)
 }
+
+func (s Steps) ParallelAll(n uint16) Step {
+	return ParallelAll(n, s...)
+}
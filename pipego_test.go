@@ -66,3 +66,43 @@ func Test_Run(t *testing.T) {
 		require.Equal(t, fmt.Errorf("mock"), err)
 	})
 }
+
+func Test_RunAll(t *testing.T) {
+	ctx := context.Background()
+	t.Run("no steps", func(t *testing.T) {
+		err := pp.RunAll(ctx)
+		require.NoError(t, err)
+	})
+	t.Run("runs all steps", func(t *testing.T) {
+		var i int
+		err := pp.RunAll(ctx,
+			func(_ context.Context) (err error) {
+				i++
+				return fmt.Errorf("a")
+			},
+			func(_ context.Context) (err error) {
+				i++
+				return fmt.Errorf("b")
+			},
+		)
+		require.Equal(t, 2, i)
+		var indexErr *pp.IndexedError
+		require.ErrorAs(t, err, &indexErr)
+		require.Equal(t, 0, indexErr.Index)
+		require.Equal(t, "step 0: a\nstep 1: b", err.Error())
+	})
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		err := pp.Steps{
+			func(_ context.Context) (err error) {
+				cancel()
+				return
+			},
+			func(_ context.Context) (err error) {
+				require.Fail(t, "should not run")
+				return
+			},
+		}.GroupAll()(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,85 @@
+// Package prommetrics implements pp.Metrics using the Prometheus client.
+// It's a separate module, so pipego itself doesn't depend on Prometheus.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	pp "github.com/sonalys/pipego"
+)
+
+// Metrics implements pp.Metrics, with all metrics labeled by step path.
+type Metrics struct {
+	started   *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+	queueWait *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+}
+
+var _ pp.Metrics = (*Metrics)(nil)
+
+// New creates and registers the metrics, prefixed by namespace.
+// Example: prommetrics.New(prometheus.DefaultRegisterer, "checkout")
+func New(reg prometheus.Registerer, namespace string) (*Metrics, error) {
+	m := &Metrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "step_started_total",
+			Help:      "Number of started steps.",
+		}, []string{"path"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "step_duration_seconds",
+			Help:      "Duration of finished steps, by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"path", "outcome"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "step_in_flight",
+			Help:      "Number of running parallel branches and channel jobs.",
+		}, []string{"path"}),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "step_queue_wait_seconds",
+			Help:      "Time parallel branches waited for the parallelism limit.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"path"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "step_retries_total",
+			Help:      "Number of retries.",
+		}, []string{"path"}),
+	}
+	for _, c := range []prometheus.Collector{m.started, m.duration, m.inFlight, m.queueWait, m.retries} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *Metrics) StepStarted(path string) {
+	m.started.WithLabelValues(path).Inc()
+}
+
+func (m *Metrics) StepFinished(path string, duration time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	m.duration.WithLabelValues(path, outcome).Observe(duration.Seconds())
+}
+
+func (m *Metrics) InFlight(path string, delta int) {
+	m.inFlight.WithLabelValues(path).Add(float64(delta))
+}
+
+func (m *Metrics) QueueWait(path string, wait time.Duration) {
+	m.queueWait.WithLabelValues(path).Observe(wait.Seconds())
+}
+
+func (m *Metrics) Retry(path string, _ int) {
+	m.retries.WithLabelValues(path).Inc()
+}
@@ -0,0 +1,42 @@
+package prommetrics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg, "test")
+	require.NoError(t, err)
+	ctx := pp.WithOptions(context.Background(), pp.WithMetrics(m))
+
+	err = pp.Run(ctx, pp.Named("checkout", pp.Parallel(2,
+		pp.Named("fetch", func(_ context.Context) error { return nil }),
+		pp.Named("pay", func(_ context.Context) error { return errors.New("mock") }),
+	)))
+	require.Error(t, err)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.started.WithLabelValues("checkout/parallel[*]/fetch")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("checkout")))
+	require.Equal(t, 3, testutil.CollectAndCount(m.duration))
+	require.Equal(t, 1, testutil.CollectAndCount(m.queueWait))
+
+	// Branch indexes are collapsed, so the number of series doesn't grow with the input.
+	err = pp.Run(ctx, pp.Named("items", pp.ForEachSeq(slices.Values(make([]int, 100)), 4, func(_, _ int) pp.Step {
+		return pp.Named("item", func(_ context.Context) error { return nil })
+	})))
+	require.NoError(t, err)
+	require.Equal(t, 100.0, testutil.ToFloat64(m.started.WithLabelValues("items/parallel[*]/item")))
+	require.Equal(t, 5, testutil.CollectAndCount(m.duration))
+
+	_, err = New(reg, "test")
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package pp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// QuorumError is returned by Quorum when not enough steps succeeded.
+type QuorumError struct {
+	Required  int
+	Succeeded int
+	// Errors holds the outcome of each step, by index, nil for the steps that succeeded.
+	Errors []error
+}
+
+func (e *QuorumError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "quorum not reached: %d of %d required steps succeeded", e.Succeeded, e.Required)
+	for i, err := range e.Errors {
+		if err != nil {
+			fmt.Fprintf(&b, "\nstep %d: %s", i, err)
+		}
+	}
+	return b.String()
+}
+
+func (e *QuorumError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Quorum runs all the given steps in parallel, and succeeds as soon as `k` of them succeed.
+// It fails as soon as reaching `k` successes becomes impossible.
+// In both cases, it cancels context for the remaining steps, and waits for them to return.
+func Quorum(k int, steps ...Step) Step {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var mu sync.Mutex
+		qErr := &QuorumError{Required: k, Errors: make([]error, len(steps))}
+		var failed int
+
+		var wg sync.WaitGroup
+		wg.Add(len(steps))
+		for i, step := range steps {
+			go func() {
+				defer wg.Done()
+				err := exec(ctx, fmt.Sprintf("quorum[%d]", i), step)
+
+				mu.Lock()
+				defer mu.Unlock()
+				qErr.Errors[i] = err
+				if err == nil {
+					qErr.Succeeded++
+				} else {
+					failed++
+				}
+				if qErr.Succeeded >= k || failed > len(steps)-k {
+					cancel()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if qErr.Succeeded >= k {
+			return nil
+		}
+		return qErr
+	}
+}
@@ -0,0 +1,50 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Quorum(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	succeed := func(_ context.Context) error { return nil }
+	fail := func(_ context.Context) error { return errMock }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, pp.Quorum(0)(ctx))
+	})
+	t.Run("quorum reached cancels stragglers", func(t *testing.T) {
+		err := pp.Quorum(2, succeed, fail, succeed, hang)(ctx)
+		require.NoError(t, err)
+	})
+	t.Run("quorum impossible cancels stragglers", func(t *testing.T) {
+		err := pp.Quorum(3, fail, succeed, fail, fail, hang)(ctx)
+		var qErr *pp.QuorumError
+		require.ErrorAs(t, err, &qErr)
+		require.Equal(t, 3, qErr.Required)
+		require.Equal(t, 1, qErr.Succeeded)
+		require.Equal(t, []error{
+			&pp.StepError{Path: "quorum[0]", Err: errMock},
+			nil,
+			&pp.StepError{Path: "quorum[2]", Err: errMock},
+			&pp.StepError{Path: "quorum[3]", Err: errMock},
+			&pp.StepError{Path: "quorum[4]", Err: context.Canceled},
+		}, qErr.Errors)
+		require.ErrorIs(t, err, errMock)
+	})
+	t.Run("more than available", func(t *testing.T) {
+		err := pp.Quorum(3, succeed, succeed)(ctx)
+		var qErr *pp.QuorumError
+		require.ErrorAs(t, err, &qErr)
+		require.Equal(t, 2, qErr.Succeeded)
+		require.Equal(t, "quorum not reached: 2 of 3 required steps succeeded", err.Error())
+	})
+}
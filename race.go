@@ -0,0 +1,58 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Race runs all the given steps in parallel,
+// It cancels context for the first successful step, and waits for the others to return.
+// It only returns an error if all steps fail, joining all of them.
+func Race(steps ...Step) Step {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errs := make([]error, len(steps))
+		var succeeded atomic.Bool
+		var wg sync.WaitGroup
+		wg.Add(len(steps))
+		for i, step := range steps {
+			go func() {
+				defer wg.Done()
+				if errs[i] = exec(ctx, fmt.Sprintf("race[%d]", i), step); errs[i] == nil {
+					succeeded.Store(true)
+					cancel()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(steps) == 0 || succeeded.Load() {
+			return nil
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// FirstOf runs the given steps sequentially, as fallbacks for each other.
+// It returns on the first successful step, and if all steps fail, their errors are joined.
+func FirstOf(steps ...Step) Step {
+	return func(ctx context.Context) error {
+		errs := make([]error, 0, len(steps))
+		for _, step := range steps {
+			if err := ctx.Err(); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			err := exec(ctx, "", step)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+}
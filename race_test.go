@@ -0,0 +1,91 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Race(t *testing.T) {
+	ctx := context.Background()
+	errA, errB := errors.New("a"), errors.New("b")
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, pp.Race()(ctx))
+	})
+	t.Run("first success cancels the rest", func(t *testing.T) {
+		var cancelled bool
+		err := pp.Race(
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				cancelled = true
+				return ctx.Err()
+			},
+			func(_ context.Context) error {
+				return nil
+			},
+		)(ctx)
+		require.NoError(t, err)
+		require.True(t, cancelled)
+	})
+	t.Run("all fail", func(t *testing.T) {
+		err := pp.Race(
+			func(_ context.Context) error { return errA },
+			func(_ context.Context) error { return errB },
+		)(ctx)
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+	})
+}
+
+func Test_FirstOf(t *testing.T) {
+	ctx := context.Background()
+	errA, errB := errors.New("a"), errors.New("b")
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, pp.FirstOf()(ctx))
+	})
+	t.Run("fallback", func(t *testing.T) {
+		var calls []int
+		err := pp.FirstOf(
+			func(_ context.Context) error {
+				calls = append(calls, 0)
+				return errA
+			},
+			func(_ context.Context) error {
+				calls = append(calls, 1)
+				return nil
+			},
+			func(_ context.Context) error {
+				require.Fail(t, "should not run")
+				return nil
+			},
+		)(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1}, calls)
+	})
+	t.Run("all fail", func(t *testing.T) {
+		err := pp.FirstOf(
+			func(_ context.Context) error { return errA },
+			func(_ context.Context) error { return errB },
+		)(ctx)
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		err := pp.FirstOf(
+			func(_ context.Context) error {
+				cancel()
+				return errA
+			},
+			func(_ context.Context) error {
+				require.Fail(t, "should not run")
+				return nil
+			},
+		)(ctx)
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,46 @@
+package pp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned when a step panics.
+type PanicError struct {
+	// Value is the value recovered from the panic.
+	Value any
+	// Stack is the stack trace of the go-routine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the recovered value, if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover converts panics inside the step into a *PanicError.
+// All steps executed by pipego are already recovered, it's useful for steps run in your own go-routines.
+func Recover(step Step) Step {
+	return func(ctx context.Context) error {
+		return call(ctx, step)
+	}
+}
+
+// call runs the step, converting panics into a *PanicError, unless Repanic is set.
+func call(ctx context.Context, step Step) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if getConfig(ctx).repanic {
+				panic(r)
+			}
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return step(ctx)
+}
@@ -0,0 +1,97 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/tracetest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Recover(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	panicking := func(_ context.Context) error {
+		panic(errMock)
+	}
+	t.Run("run", func(t *testing.T) {
+		err := pp.Run(ctx, panicking)
+		var panicErr *pp.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, errMock, panicErr.Value)
+		require.ErrorIs(t, err, errMock)
+		require.Contains(t, string(panicErr.Stack), "recover_test.go")
+	})
+	t.Run("parallel cancels siblings", func(t *testing.T) {
+		err := pp.Parallel(2,
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			func(_ context.Context) error {
+				panic("mock")
+			},
+		)(ctx)
+		var panicErr *pp.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "mock", panicErr.Value)
+	})
+	t.Run("chan divide", func(t *testing.T) {
+		ch := make(chan int, 1)
+		var recv <-chan int = ch
+		ch <- 1
+		err := pp.ChanDivide(&recv, func(_ context.Context, _ int) error {
+			panic("mock")
+		})(ctx)
+		var panicErr *pp.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		close(ch)
+	})
+	t.Run("interceptors see the panic", func(t *testing.T) {
+		var recorder tracetest.Recorder
+		metrics := &finishedMetrics{}
+		ctx := pp.WithOptions(ctx, pp.WithTracer(&recorder), pp.WithMetrics(metrics))
+		report, err := pp.RunWithReport(ctx, pp.Named("boom", panicking))
+		var panicErr *pp.PanicError
+		require.ErrorAs(t, err, &panicErr)
+
+		require.ErrorAs(t, metrics.errs["boom"], &panicErr)
+		spans := recorder.Spans()
+		require.Len(t, spans, 1)
+		require.ErrorAs(t, spans[0].Err, &panicErr)
+		require.Len(t, report.Children, 1)
+		require.Equal(t, pp.StatusFailed, report.Children[0].Status)
+	})
+	t.Run("repanic", func(t *testing.T) {
+		ctx := pp.WithOptions(ctx, pp.Repanic())
+		require.PanicsWithValue(t, errMock, func() {
+			_ = pp.Run(ctx, panicking)
+		})
+	})
+	t.Run("recover", func(t *testing.T) {
+		err := pp.Recover(panicking)(ctx)
+		require.ErrorIs(t, err, errMock)
+	})
+}
+
+type finishedMetrics struct {
+	errs map[string]error
+}
+
+func (m *finishedMetrics) StepStarted(string) {}
+
+func (m *finishedMetrics) StepFinished(path string, _ time.Duration, err error) {
+	if m.errs == nil {
+		m.errs = map[string]error{}
+	}
+	m.errs[path] = err
+}
+
+func (m *finishedMetrics) InFlight(string, int) {}
+
+func (m *finishedMetrics) QueueWait(string, time.Duration) {}
+
+func (m *finishedMetrics) Retry(string, int) {}
@@ -0,0 +1,128 @@
+package pp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a step in a Report.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	// StatusCancelled is used for steps returning context.Canceled or context.DeadlineExceeded.
+	StatusCancelled Status = "cancelled"
+	// StatusSkipped is used for steps that never ran, because a previous step failed.
+	StatusSkipped Status = "skipped"
+)
+
+// Report is the execution report of a step, mirroring the pipeline structure through its children.
+// Retry attempts are reported as children of the retry step.
+type Report struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Attempts is set for retry steps.
+	Attempts int       `json:"attempts,omitempty"`
+	Status   Status    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Children []*Report `json:"children,omitempty"`
+
+	mu sync.Mutex
+	// sealed is set when RunWithReport returns, ignoring later updates from abandoned go-routines.
+	sealed bool
+}
+
+type reportKey struct{}
+
+// RunWithReport works like Run, but also returns the execution report of the pipeline.
+func RunWithReport(ctx context.Context, steps ...Step) (*Report, error) {
+	root := &Report{Name: "pipeline", Path: Path(ctx), Start: time.Now()}
+	err := runSteps(context.WithValue(ctx, reportKey{}, root), steps...)
+	root.finish(err)
+	root.seal()
+	return root, err
+}
+
+func (r *Report) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sealed {
+		return
+	}
+	r.Duration = time.Since(r.Start)
+	switch {
+	case err == nil:
+		r.Status = StatusSucceeded
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		r.Status = StatusCancelled
+	default:
+		r.Status = StatusFailed
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+func (r *Report) addChild(child *Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.sealed {
+		r.Children = append(r.Children, child)
+	}
+}
+
+// seal marks the steps still running, like abandoned hedges or timed out attempts, as cancelled,
+// and stops all later updates, so the report can be read safely.
+func (r *Report) seal() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Status == "" {
+		r.Duration = time.Since(r.Start)
+		r.Status = StatusCancelled
+	}
+	r.sealed = true
+	for _, child := range r.Children {
+		child.seal()
+	}
+}
+
+// reported adds a child report to parent for each step execution.
+func reported(parent *Report, step Step) Step {
+	return func(ctx context.Context) (err error) {
+		node := &Report{Name: spanName(ctx), Path: Path(ctx), Start: time.Now()}
+		parent.addChild(node)
+		defer func() { node.finish(err) }()
+		return step(context.WithValue(ctx, reportKey{}, node))
+	}
+}
+
+// reportRetry sets the number of attempts of the retry step running with ctx.
+func reportRetry(ctx context.Context, attempt int) {
+	if node, ok := ctx.Value(reportKey{}).(*Report); ok {
+		node.mu.Lock()
+		defer node.mu.Unlock()
+		if !node.sealed {
+			node.Attempts = attempt + 1
+		}
+	}
+}
+
+// reportSkipped adds the steps that never ran to the report.
+func reportSkipped(ctx context.Context, steps ...Step) {
+	parent, ok := ctx.Value(reportKey{}).(*Report)
+	if !ok {
+		return
+	}
+	for _, step := range steps {
+		stepCtx := ctx
+		if name := nameOf(step); name != "" {
+			stepCtx = withSegment(ctx, name)
+		}
+		parent.addChild(&Report{Name: spanName(stepCtx), Path: Path(stepCtx), Status: StatusSkipped})
+	}
+}
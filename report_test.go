@@ -0,0 +1,123 @@
+package pp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunWithReport(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	var attempts int
+
+	report, err := pp.RunWithReport(ctx,
+		pp.Named("checkout", pp.Parallel(2,
+			pp.Named("fetch", func(_ context.Context) error { return nil }),
+			pp.Named("pay", retry.Constant(3, time.Millisecond, pp.Named("call", func(_ context.Context) error {
+				if attempts++; attempts < 3 {
+					return errMock
+				}
+				return nil
+			}))),
+		)),
+		pp.Named("ship", func(ctx context.Context) error {
+			return errMock
+		}),
+		pp.Named("notify", func(ctx context.Context) error {
+			require.Fail(t, "should not run")
+			return nil
+		}),
+	)
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, "pipeline", report.Name)
+	require.Equal(t, pp.StatusFailed, report.Status)
+	require.Equal(t, "ship: mock", report.Error)
+
+	type node struct {
+		Path     string
+		Status   pp.Status
+		Attempts int
+		Children []node
+	}
+	var simplify func(r *pp.Report) node
+	simplify = func(r *pp.Report) node {
+		n := node{Path: r.Path, Status: r.Status, Attempts: r.Attempts}
+		for _, child := range r.Children {
+			n.Children = append(n.Children, simplify(child))
+		}
+		// Parallel branches are reported in start order.
+		slices.SortFunc(n.Children, func(a, b node) int { return strings.Compare(a.Path, b.Path) })
+		return n
+	}
+	call := node{Path: "checkout/parallel[1]/pay/call", Status: pp.StatusFailed}
+	require.Equal(t, node{Path: "", Status: pp.StatusFailed, Children: []node{
+		{Path: "checkout", Status: pp.StatusSucceeded, Children: []node{
+			{Path: "checkout/parallel[0]/fetch", Status: pp.StatusSucceeded},
+			{Path: "checkout/parallel[1]/pay", Status: pp.StatusSucceeded, Attempts: 3, Children: []node{
+				call, call, {Path: call.Path, Status: pp.StatusSucceeded},
+			}},
+		}},
+		{Path: "notify", Status: pp.StatusSkipped},
+		{Path: "ship", Status: pp.StatusFailed},
+	}}, simplify(report))
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded pp.Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, report.Children[0].Children[0].Name, decoded.Children[0].Children[0].Name)
+}
+
+func Test_RunWithReport_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err := pp.RunWithReport(ctx,
+		func(ctx context.Context) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, pp.StatusCancelled, report.Status)
+	require.Len(t, report.Children, 1)
+	require.Equal(t, pp.StatusCancelled, report.Children[0].Status)
+}
+
+func Test_RunWithReport_abandoned(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	report, err := pp.RunWithReport(ctx,
+		pp.Hedge(time.Millisecond, 1, func(_ context.Context) error {
+			if calls.Add(1) == 1 {
+				// The first copy ignores cancellation, and finishes after the report is returned.
+				defer close(finished)
+				<-release
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	close(release)
+	<-finished
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"status":""`)
+	hedges := report.Children[0].Children
+	require.Len(t, hedges, 2)
+	slices.SortFunc(hedges, func(a, b *pp.Report) int { return strings.Compare(a.Path, b.Path) })
+	require.Equal(t, pp.StatusCancelled, hedges[0].Status)
+	require.Equal(t, pp.StatusSucceeded, hedges[1].Status)
+}
@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// Stop is returned by a Retrier to signal that no more retries should be done.
+// Decorators like FullJitter return it unchanged.
+const Stop time.Duration = -1
+
+// toDuration converts a float to duration, saturating instead of overflowing.
+func toDuration(f float64) time.Duration {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(f)
+}
+
+// limit applies a maximum delay, and a maximum total elapsed time to the delays returned by the delay func.
+// Zero values disable the limits.
+func limit(n int, delay func(int) time.Duration, max, maxElapsed time.Duration) time.Duration {
+	capped := func(n int) time.Duration {
+		if d := delay(n); max <= 0 || d < max {
+			return d
+		}
+		return max
+	}
+	if maxElapsed > 0 {
+		var elapsed time.Duration
+		for i := 0; i <= n; i++ {
+			if elapsed += capped(i); elapsed > maxElapsed || elapsed < 0 {
+				return Stop
+			}
+		}
+	}
+	return capped(n)
+}
+
+// Exponential is a multiplicative backoff.
+// It returns Base * Multiplier ^ n, capped by Max.
+// If the sum of all delays exceeds MaxElapsed, it returns Stop.
+// Example: 1s, x2: 1s, 2s, 4s, 8s...
+type Exponential struct {
+	Base time.Duration
+	// Multiplier defaults to 2.
+	Multiplier float64
+	// Max is the maximum delay between retries, 0 means no limit.
+	Max time.Duration
+	// MaxElapsed is the maximum sum of all delays, 0 means no limit.
+	MaxElapsed time.Duration
+}
+
+func (r Exponential) delay(n int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	return toDuration(float64(r.Base) * math.Pow(multiplier, float64(n)))
+}
+
+func (r Exponential) Retry(n int) time.Duration {
+	return limit(n, r.delay, r.Max, r.MaxElapsed)
+}
+
+func (r Exponential) MaxDelay() time.Duration { return r.Max }
+
+// Fibonacci is a backoff following the fibonacci sequence.
+// It returns Base * fib(n + 1), capped by Max.
+// If the sum of all delays exceeds MaxElapsed, it returns Stop.
+// Example: 1s: 1s, 1s, 2s, 3s, 5s...
+type Fibonacci struct {
+	Base time.Duration
+	// Max is the maximum delay between retries, 0 means no limit.
+	Max time.Duration
+	// MaxElapsed is the maximum sum of all delays, 0 means no limit.
+	MaxElapsed time.Duration
+}
+
+func (r Fibonacci) delay(n int) time.Duration {
+	a, b := 0.0, 1.0
+	for i := 0; i < n; i++ {
+		a, b = b, a+b
+	}
+	return toDuration(float64(r.Base) * b)
+}
+
+func (r Fibonacci) Retry(n int) time.Duration {
+	return limit(n, r.delay, r.Max, r.MaxElapsed)
+}
+
+func (r Fibonacci) MaxDelay() time.Duration { return r.Max }
+
+// Polynomial is a backoff following a polynomial curve.
+// It returns Base * (n + 1) ^ Exponent, capped by Max.
+// If the sum of all delays exceeds MaxElapsed, it returns Stop.
+// Example: 1s, ^2: 1s, 4s, 9s, 16s...
+type Polynomial struct {
+	Base     time.Duration
+	Exponent float64
+	// Max is the maximum delay between retries, 0 means no limit.
+	Max time.Duration
+	// MaxElapsed is the maximum sum of all delays, 0 means no limit.
+	MaxElapsed time.Duration
+}
+
+func (r Polynomial) delay(n int) time.Duration {
+	return toDuration(float64(r.Base) * math.Pow(float64(n+1), r.Exponent))
+}
+
+func (r Polynomial) Retry(n int) time.Duration {
+	return limit(n, r.delay, r.Max, r.MaxElapsed)
+}
+
+func (r Polynomial) MaxDelay() time.Duration { return r.Max }
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exponential(t *testing.T) {
+	r := Exponential{Base: time.Second, Multiplier: 2, Max: 10 * time.Second}
+	expSlice := []time.Duration{
+		1 * time.Second,  // 1s * 2 ^ 0
+		2 * time.Second,  // 1s * 2 ^ 1
+		4 * time.Second,  // 1s * 2 ^ 2
+		8 * time.Second,  // 1s * 2 ^ 3
+		10 * time.Second, // Max
+	}
+	for i := range expSlice {
+		require.Equal(t, expSlice[i], r.Retry(i))
+	}
+	t.Run("default multiplier", func(t *testing.T) {
+		require.Equal(t, 4*time.Second, Exponential{Base: time.Second}.Retry(2))
+	})
+	t.Run("does not overflow", func(t *testing.T) {
+		require.Equal(t, time.Duration(math.MaxInt64), Exponential{Base: time.Second}.Retry(1000))
+	})
+	t.Run("max elapsed", func(t *testing.T) {
+		r := Exponential{Base: time.Second, MaxElapsed: 7 * time.Second}
+		require.Equal(t, 4*time.Second, r.Retry(2)) // 1s + 2s + 4s = 7s
+		require.Equal(t, Stop, r.Retry(3))
+	})
+}
+
+func Test_Fibonacci(t *testing.T) {
+	r := Fibonacci{Base: time.Second, Max: 6 * time.Second}
+	expSlice := []time.Duration{
+		1 * time.Second,
+		1 * time.Second,
+		2 * time.Second,
+		3 * time.Second,
+		5 * time.Second,
+		6 * time.Second, // Max
+	}
+	for i := range expSlice {
+		require.Equal(t, expSlice[i], r.Retry(i))
+	}
+}
+
+func Test_Polynomial(t *testing.T) {
+	r := Polynomial{Base: time.Second, Exponent: 2, MaxElapsed: 14 * time.Second}
+	expSlice := []time.Duration{
+		1 * time.Second,
+		4 * time.Second,
+		9 * time.Second,
+		Stop, // 1s + 4s + 9s + 16s > 14s
+	}
+	for i := range expSlice {
+		require.Equal(t, expSlice[i], r.Retry(i))
+	}
+}
+
+func Test_RetrierStop(t *testing.T) {
+	var calls int
+	errMock := errors.New("mock")
+	err := Retry(Inf, Fibonacci{Base: time.Millisecond, MaxElapsed: 2 * time.Millisecond}, func(_ context.Context) error {
+		calls++
+		return errMock
+	})(context.Background())
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, 3, calls)
+}
+
+func Test_JitterStop(t *testing.T) {
+	stop := Exponential{Base: time.Millisecond, MaxElapsed: time.Millisecond}
+	for name, r := range map[string]Retrier{
+		"full":         FullJitter(stop, maxRand{}),
+		"equal":        EqualJitter(stop, maxRand{}),
+		"decorrelated": DecorrelatedJitter(stop, time.Second, maxRand{}),
+		"nested":       FullJitter(EqualJitter(stop, maxRand{}), maxRand{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, Stop, r.Retry(1))
+
+			var calls int
+			err := Retry(Inf, r, func(_ context.Context) error {
+				calls++
+				return errors.New("mock")
+			})(context.Background())
+			require.ErrorIs(t, err, ErrMaxElapsed)
+			require.Equal(t, 2, calls)
+		})
+	}
+}
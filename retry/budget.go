@@ -0,0 +1,42 @@
+package retry
+
+import "sync"
+
+// Budget is a token bucket limiting retries relative to the number of requests.
+// Each request deposits ratio tokens, and each retry withdraws one.
+// It's safe for concurrent use, and should be shared by all retry steps calling the same dependency.
+type Budget struct {
+	mu     sync.Mutex
+	ratio  float64
+	tokens float64
+	max    float64
+}
+
+// NewBudget creates a retry budget allowing retries to be at most ratio of the requests.
+// burst is the maximum number of retries that can be accumulated, and the budget starts full.
+// Example: NewBudget(0.1, 10) allows 10% of retries, with bursts of up to 10 retries.
+func NewBudget(ratio float64, burst int) *Budget {
+	return &Budget{
+		ratio:  ratio,
+		tokens: float64(burst),
+		max:    float64(burst),
+	}
+}
+
+// deposit records a new request.
+func (b *Budget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.max)
+}
+
+// withdraw reports whether a retry is allowed, consuming a token if it is.
+func (b *Budget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Budget(t *testing.T) {
+	b := NewBudget(0.5, 2)
+	require.True(t, b.withdraw())
+	require.True(t, b.withdraw())
+	require.False(t, b.withdraw())
+	b.deposit()
+	require.False(t, b.withdraw())
+	b.deposit()
+	require.True(t, b.withdraw())
+	// Deposits are capped by burst.
+	for range 10 {
+		b.deposit()
+	}
+	require.True(t, b.withdraw())
+	require.True(t, b.withdraw())
+	require.False(t, b.withdraw())
+}
+
+func Test_UseBudget(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	// The budget is shared between both retry steps.
+	policy := With(UseBudget(NewBudget(0, 2)))
+	var calls int
+	step := func(_ context.Context) error {
+		calls++
+		return errMock
+	}
+	err := policy.Constant(2, time.Millisecond, step)(ctx)
+	require.ErrorIs(t, err, ErrMaxAttempts)
+	require.Equal(t, 2, calls)
+
+	err = policy.Constant(Inf, time.Millisecond, step)(ctx)
+	require.ErrorIs(t, err, ErrBudgetExhausted)
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, 4, calls)
+}
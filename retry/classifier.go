@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error to signal it should not be retried.
+// The retry step stops immediately, returning the wrapped error.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// RetryAfterError is implemented by errors that know how long to wait before retrying,
+// like HTTP 429 or 503 responses with a Retry-After header.
+// Its delay is used instead of the Retrier's, capped by MaxDelay and by the Retrier's own maximum delay.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// Capped is implemented by Retriers with a maximum delay, like Exponential.
+// It also caps the delays provided by a RetryAfterError.
+type Capped interface {
+	Retrier
+	// MaxDelay returns the maximum delay between retries, 0 means no limit.
+	MaxDelay() time.Duration
+}
+
+// maxDelay returns the maximum delay of r, or 0 if it has no limit.
+func maxDelay(r Retrier) time.Duration {
+	if c, ok := r.(Capped); ok {
+		return c.MaxDelay()
+	}
+	return 0
+}
+
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+func (e *retryAfterError) RetryAfter() time.Duration { return e.delay }
+
+// After wraps an error, asking the retry step to wait for the given delay before the next attempt.
+func After(delay time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err, delay}
+}
+
+// Is returns a classifier that matches any of the given targets, using errors.Is.
+func Is(targets ...error) func(error) bool {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// As returns a classifier that matches errors of type T, using errors.As.
+func As[T error]() func(error) bool {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// Not inverts the given classifier.
+// Example: retry.If(retry.Not(retry.Is(ErrValidation)))
+func Not(classifier func(error) bool) func(error) bool {
+	return func(err error) bool {
+		return !classifier(err)
+	}
+}
+
+// retryable reports whether err should be retried, and the error to return otherwise.
+func (c config) retryable(err error) (bool, error) {
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return false, perm.err
+	}
+	for _, classifier := range c.retryIf {
+		if !classifier(err) {
+			return false, err
+		}
+	}
+	return true, err
+}
+
+// delay returns the delay before the next attempt, preferring the one provided by the error.
+func (c config) delay(r Retrier, n int, err error) time.Duration {
+	delay := r.Retry(n)
+	if delay == Stop {
+		return Stop
+	}
+	var retryAfter RetryAfterError
+	if errors.As(err, &retryAfter) {
+		delay = retryAfter.RetryAfter()
+		if max := maxDelay(r); max > 0 {
+			delay = min(delay, max)
+		}
+	}
+	if c.maxDelay > 0 {
+		delay = min(delay, c.maxDelay)
+	}
+	return max(delay, 0)
+}
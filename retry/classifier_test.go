@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockError struct{}
+
+func (mockError) Error() string { return "mock error" }
+
+func Test_Classifiers(t *testing.T) {
+	errMock := errors.New("mock")
+	wrapped := fmt.Errorf("wrapped: %w", errMock)
+	require.True(t, Is(errMock)(wrapped))
+	require.True(t, Is(context.Canceled, errMock)(wrapped))
+	require.False(t, Is(context.Canceled)(wrapped))
+	require.True(t, As[mockError]()(fmt.Errorf("wrapped: %w", mockError{})))
+	require.False(t, As[mockError]()(wrapped))
+	require.False(t, Not(Is(errMock))(wrapped))
+	require.Nil(t, Permanent(nil))
+}
+
+func Test_RetryIf(t *testing.T) {
+	ctx := context.Background()
+	errTransient := errors.New("transient")
+	errValidation := errors.New("validation")
+	t.Run("retries matching errors", func(t *testing.T) {
+		var calls int
+		err := With(If(Is(errTransient))).Constant(3, time.Millisecond, func(_ context.Context) error {
+			calls++
+			return errTransient
+		})(ctx)
+		require.ErrorIs(t, err, errTransient)
+		require.Equal(t, 3, calls)
+	})
+	t.Run("stops on non matching errors", func(t *testing.T) {
+		var calls int
+		err := With(If(Is(errTransient))).Linear(3, time.Millisecond, func(_ context.Context) error {
+			calls++
+			return errValidation
+		})(ctx)
+		require.Equal(t, errValidation, err)
+		require.Equal(t, 1, calls)
+	})
+	t.Run("permanent error", func(t *testing.T) {
+		var calls int
+		err := Exp(Inf, time.Millisecond, time.Millisecond, 2, func(_ context.Context) error {
+			calls++
+			return Permanent(errValidation)
+		})(ctx)
+		require.Equal(t, errValidation, err)
+		require.Equal(t, 1, calls)
+	})
+	t.Run("custom retrier", func(t *testing.T) {
+		var calls int
+		err := With(If(Not(Is(errValidation)))).Retry(Inf, constantRetry{time.Millisecond}, func(_ context.Context) error {
+			calls++
+			if calls == 2 {
+				return errValidation
+			}
+			return errTransient
+		})(ctx)
+		require.Equal(t, errValidation, err)
+		require.Equal(t, 2, calls)
+	})
+}
+
+func Test_RetryAfter(t *testing.T) {
+	errMock := errors.New("mock")
+	cfg := config{}
+	require.Equal(t, time.Hour, cfg.delay(ConstantDelay(time.Hour), 0, errMock))
+	require.Equal(t, time.Millisecond, cfg.delay(ConstantDelay(time.Hour), 0, After(time.Millisecond, errMock)))
+	require.Equal(t, time.Millisecond, cfg.delay(ConstantDelay(time.Hour), 0, fmt.Errorf("wrapped: %w", After(time.Millisecond, errMock))))
+	require.Equal(t, Stop, cfg.delay(Exponential{Base: time.Hour, MaxElapsed: time.Minute}, 0, After(time.Millisecond, errMock)))
+	require.Nil(t, After(time.Second, nil))
+
+	// The Retrier's own maximum also caps the server-provided delay.
+	exp := Exponential{Base: time.Second, Max: 10 * time.Second}
+	require.Equal(t, 10*time.Second, cfg.delay(exp, 0, After(time.Hour, errMock)))
+	require.Equal(t, 10*time.Second, cfg.delay(FullJitter(exp, nil), 0, After(time.Hour, errMock)))
+	require.Equal(t, time.Minute, cfg.delay(DecorrelatedJitter(exp, time.Minute, nil), 0, After(time.Hour, errMock)))
+	require.Equal(t, time.Hour, cfg.delay(Exponential{Base: time.Second}, 0, After(time.Hour, errMock)))
+
+	cfg = With(MaxDelay(time.Second)).config()
+	require.Equal(t, time.Second, cfg.delay(ConstantDelay(time.Hour), 0, After(time.Hour, errMock)))
+	require.Equal(t, time.Second, cfg.delay(ConstantDelay(time.Hour), 0, errMock))
+
+	t.Run("retry loop", func(t *testing.T) {
+		var calls int
+		t1 := time.Now()
+		err := Constant(2, time.Hour, func(_ context.Context) error {
+			calls++
+			return After(time.Millisecond, errMock)
+		})(context.Background())
+		require.ErrorIs(t, err, errMock)
+		require.Equal(t, 2, calls)
+		require.Less(t, time.Since(t1), time.Second)
+	})
+}
@@ -0,0 +1,145 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Rand is the random source used by jitter strategies.
+// *rand.Rand from math/rand/v2 implements it, but it's not safe for concurrent use.
+type Rand interface {
+	Int64N(n int64) int64
+}
+
+type globalRand struct{}
+
+func (globalRand) Int64N(n int64) int64 { return rand.Int64N(n) }
+
+func randOrDefault(src Rand) Rand {
+	if src == nil {
+		return globalRand{}
+	}
+	return src
+}
+
+// between returns a random duration in [min, max).
+func between(src Rand, min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(src.Int64N(int64(max-min)))
+}
+
+// Stateful is implemented by Retriers keeping state between the retries of a single run, like DecorrelatedJitter.
+// Fresh is called on each execution of a retry step, so concurrent runs don't share their state.
+type Stateful interface {
+	Retrier
+	Fresh() Retrier
+}
+
+// fresh returns a copy of r without state, if it keeps any.
+func fresh(r Retrier) Retrier {
+	if s, ok := r.(Stateful); ok {
+		return s.Fresh()
+	}
+	return r
+}
+
+type fullJitter struct {
+	Retrier
+	src Rand
+}
+
+func (r fullJitter) Retry(n int) time.Duration {
+	delay := r.Retrier.Retry(n)
+	if delay == Stop {
+		return Stop
+	}
+	return between(r.src, 0, delay)
+}
+
+func (r fullJitter) MaxDelay() time.Duration { return maxDelay(r.Retrier) }
+
+func (r fullJitter) Fresh() Retrier {
+	return fullJitter{fresh(r.Retrier), r.src}
+}
+
+// FullJitter decorates a Retrier, returning a random delay between 0 and the original delay.
+// A nil src uses the global random source.
+// Example: 4s: [0s, 4s)
+func FullJitter(r Retrier, src Rand) Retrier {
+	return fullJitter{r, randOrDefault(src)}
+}
+
+type equalJitter struct {
+	Retrier
+	src Rand
+}
+
+func (r equalJitter) Retry(n int) time.Duration {
+	delay := r.Retrier.Retry(n)
+	if delay == Stop {
+		return Stop
+	}
+	return between(r.src, delay/2, delay)
+}
+
+func (r equalJitter) MaxDelay() time.Duration { return maxDelay(r.Retrier) }
+
+func (r equalJitter) Fresh() Retrier {
+	return equalJitter{fresh(r.Retrier), r.src}
+}
+
+// EqualJitter decorates a Retrier, keeping half of the original delay and randomizing the other half.
+// A nil src uses the global random source.
+// Example: 4s: [2s, 4s)
+func EqualJitter(r Retrier, src Rand) Retrier {
+	return equalJitter{r, randOrDefault(src)}
+}
+
+type decorrelatedJitter struct {
+	Retrier
+	max time.Duration
+	src Rand
+
+	mu   sync.Mutex
+	prev time.Duration
+}
+
+func (r *decorrelatedJitter) Retry(n int) time.Duration {
+	base := r.Retrier.Retry(n)
+	if base == Stop {
+		return Stop
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n == 0 || r.prev < base {
+		r.prev = base
+	}
+	upper := time.Duration(math.MaxInt64)
+	if r.prev < upper/3 {
+		upper = 3 * r.prev
+	}
+	r.prev = between(r.src, base, upper)
+	if r.max > 0 {
+		r.prev = min(r.prev, r.max)
+	}
+	return r.prev
+}
+
+func (r *decorrelatedJitter) MaxDelay() time.Duration { return max(r.max, 0) }
+
+func (r *decorrelatedJitter) Fresh() Retrier {
+	return &decorrelatedJitter{Retrier: fresh(r.Retrier), max: r.max, src: r.src}
+}
+
+// DecorrelatedJitter decorates a Retrier, returning a random delay between the original delay
+// and 3 times the previous returned delay, capped by max. A max of 0 or less means no limit.
+// Each execution of a retry step keeps its own previous delay, so it can be shared between concurrent runs.
+// A nil src uses the global random source.
+// Example: 1s: [1s, 3s), [1s, 3 * prev), ...
+func DecorrelatedJitter(r Retrier, max time.Duration, src Rand) Retrier {
+	return &decorrelatedJitter{Retrier: r, max: max, src: randOrDefault(src)}
+}
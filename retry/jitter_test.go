@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// maxRand always returns the biggest possible value.
+type maxRand struct{}
+
+func (maxRand) Int64N(n int64) int64 { return n - 1 }
+
+func Test_FullJitter(t *testing.T) {
+	r := FullJitter(ConstantDelay(time.Second), rand.New(rand.NewPCG(1, 2)))
+	for i := 0; i < 100; i++ {
+		delay := r.Retry(i)
+		require.GreaterOrEqual(t, delay, time.Duration(0))
+		require.Less(t, delay, time.Second)
+	}
+	require.Equal(t, time.Second-1, FullJitter(ConstantDelay(time.Second), maxRand{}).Retry(0))
+	require.Equal(t, time.Duration(0), FullJitter(ConstantDelay(0), nil).Retry(0))
+}
+
+func Test_EqualJitter(t *testing.T) {
+	r := EqualJitter(LinearDelay(time.Second), rand.New(rand.NewPCG(1, 2)))
+	for i := 1; i <= 10; i++ {
+		delay := r.Retry(i)
+		require.GreaterOrEqual(t, delay, time.Duration(i)*time.Second/2)
+		require.Less(t, delay, time.Duration(i)*time.Second)
+	}
+}
+
+func Test_DecorrelatedJitter(t *testing.T) {
+	r := DecorrelatedJitter(ConstantDelay(time.Second), 10*time.Second, maxRand{})
+	expSlice := []time.Duration{
+		3*time.Second - 1,
+		9*time.Second - 4,
+		10 * time.Second, // max
+		10 * time.Second,
+	}
+	for i := range expSlice {
+		require.Equal(t, expSlice[i], r.Retry(i))
+	}
+	// Restarting resets the previous delay.
+	require.Equal(t, 3*time.Second-1, r.Retry(0))
+
+	// Each retry run keeps its own previous delay.
+	first, second := r.(Stateful).Fresh(), r.(Stateful).Fresh()
+	require.Equal(t, 3*time.Second-1, first.Retry(0))
+	require.Equal(t, 9*time.Second-4, first.Retry(1))
+	require.Equal(t, 3*time.Second-1, second.Retry(0))
+	require.Equal(t, 10*time.Second, first.Retry(2))
+	// Decorators forward the fresh copy.
+	wrapped := FullJitter(r, maxRand{}).(Stateful).Fresh()
+	require.Equal(t, 3*time.Second-2, wrapped.Retry(0))
+
+	// No max means no limit.
+	r = DecorrelatedJitter(ConstantDelay(time.Second), 0, maxRand{})
+	require.Equal(t, 3*time.Second-1, r.Retry(0))
+	require.Equal(t, 9*time.Second-4, r.Retry(1))
+	for i := 2; i < 100; i++ {
+		require.Greater(t, r.Retry(i), time.Second)
+	}
+}
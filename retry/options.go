@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"time"
+
+	pp "github.com/sonalys/pipego"
+)
+
+type config struct {
+	retryIf        []func(error) bool
+	maxElapsed     time.Duration
+	attemptTimeout time.Duration
+	onRetry        []func(attempt int, err error, nextDelay time.Duration)
+	budget         *Budget
+	maxDelay       time.Duration
+}
+
+// Option configures the behavior of a retry step.
+type Option func(*config)
+
+// Policy holds a set of options, and builds retry steps with them.
+type Policy struct {
+	opts []Option
+}
+
+// With creates a Policy from the given options.
+// Example: retry.With(retry.If(isTransient)).Constant(3, time.Second, step)
+func With(opts ...Option) Policy {
+	return Policy{opts: opts}
+}
+
+func (p Policy) config() config {
+	var cfg config
+	for _, opt := range p.opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Retry retries all the given steps using the provided Retrier to calculate delays.
+func (p Policy) Retry(n int, r Retrier, steps ...pp.Step) pp.Step {
+	return newRetry(n, r, p.config(), steps...)
+}
+
+// Constant is the Policy version of the package level Constant.
+func (p Policy) Constant(n int, delay time.Duration, steps ...pp.Step) pp.Step {
+	return p.Retry(n, constantRetry{delay}, steps...)
+}
+
+// Linear is the Policy version of the package level Linear.
+func (p Policy) Linear(n int, delay time.Duration, steps ...pp.Step) pp.Step {
+	return p.Retry(n, linearRetry{delay}, steps...)
+}
+
+// Exp is the Policy version of the package level Exp.
+func (p Policy) Exp(n int, initialDelay, maxDelay time.Duration, exp float64, steps ...pp.Step) pp.Step {
+	return p.Retry(n, Exponential{Base: initialDelay, Multiplier: exp, Max: maxDelay}, steps...)
+}
+
+// If only retries errors for which the classifier returns true.
+// When used multiple times, all classifiers must agree for the error to be retried.
+func If(classifier func(error) bool) Option {
+	return func(c *config) {
+		c.retryIf = append(c.retryIf, classifier)
+	}
+}
+
+// MaxElapsed limits the total time spent retrying, including attempts and delays.
+// When exhausted, the returned error wraps ErrMaxElapsed.
+func MaxElapsed(d time.Duration) Option {
+	return func(c *config) {
+		c.maxElapsed = d
+	}
+}
+
+// AttemptTimeout gives each attempt its own timeout.
+// A timed out attempt is abandoned, and the next one is started.
+func AttemptTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.attemptTimeout = d
+	}
+}
+
+// OnRetry registers a hook called after a failed attempt, before waiting for the next one.
+// attempt starts from 1.
+func OnRetry(hook func(attempt int, err error, nextDelay time.Duration)) Option {
+	return func(c *config) {
+		c.onRetry = append(c.onRetry, hook)
+	}
+}
+
+// UseBudget consults the given budget before each retry.
+// When the budget is exhausted, the returned error wraps ErrBudgetExhausted.
+func UseBudget(b *Budget) Option {
+	return func(c *config) {
+		c.budget = b
+	}
+}
+
+// MaxDelay caps all delays between attempts, including the ones provided by a RetryAfterError.
+func MaxDelay(d time.Duration) Option {
+	return func(c *config) {
+		c.maxDelay = d
+	}
+}
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MaxAttempts(t *testing.T) {
+	errMock := errors.New("mock")
+	err := Constant(2, time.Millisecond, func(_ context.Context) error {
+		return errMock
+	})(context.Background())
+	require.ErrorIs(t, err, ErrMaxAttempts)
+	require.ErrorIs(t, err, errMock)
+	require.NotErrorIs(t, err, ErrMaxElapsed)
+}
+
+func Test_MaxElapsed(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("delay exceeds budget", func(t *testing.T) {
+		var calls int
+		t1 := time.Now()
+		err := With(MaxElapsed(50*time.Millisecond)).Constant(Inf, time.Hour, func(_ context.Context) error {
+			calls++
+			return errMock
+		})(ctx)
+		require.ErrorIs(t, err, ErrMaxElapsed)
+		require.ErrorIs(t, err, errMock)
+		require.Equal(t, 1, calls)
+		require.Less(t, time.Since(t1), time.Second)
+	})
+	t.Run("attempt exceeds budget", func(t *testing.T) {
+		err := With(MaxElapsed(10*time.Millisecond)).Constant(Inf, time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})(ctx)
+		require.ErrorIs(t, err, ErrMaxElapsed)
+		require.NotErrorIs(t, err, ErrMaxAttempts)
+	})
+	t.Run("retrier stop", func(t *testing.T) {
+		err := Retry(Inf, Exponential{Base: time.Millisecond, MaxElapsed: time.Millisecond}, func(_ context.Context) error {
+			return errMock
+		})(ctx)
+		require.ErrorIs(t, err, ErrMaxElapsed)
+	})
+}
+
+func Test_AttemptTimeout(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	err := With(AttemptTimeout(10*time.Millisecond), If(Is(context.Canceled))).Constant(3, time.Millisecond, func(ctx context.Context) error {
+		if calls.Add(1) < 3 {
+			// Hangs, ignoring the context.
+			time.Sleep(time.Second)
+		}
+		return nil
+	})(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, calls.Load())
+
+	err = With(AttemptTimeout(time.Millisecond)).Constant(2, time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})(ctx)
+	require.ErrorIs(t, err, ErrAttemptTimeout)
+	require.ErrorIs(t, err, ErrMaxAttempts)
+}
+
+func Test_OnRetry(t *testing.T) {
+	type call struct {
+		attempt int
+		err     error
+		delay   time.Duration
+	}
+	var calls []call
+	var attempts []int
+	errMock := errors.New("mock")
+	err := With(OnRetry(func(attempt int, err error, nextDelay time.Duration) {
+		calls = append(calls, call{attempt, err, nextDelay})
+	})).Linear(3, time.Millisecond, func(ctx context.Context) error {
+		attempts = append(attempts, Attempt(ctx))
+		return errMock
+	})(context.Background())
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, []int{1, 2, 3}, attempts)
+	require.Equal(t, []call{
+		{1, errMock, 0},
+		{2, errMock, time.Millisecond},
+	}, calls)
+	require.Zero(t, Attempt(context.Background()))
+}
@@ -2,7 +2,8 @@ package retry
 
 import (
 	"context"
-	"math"
+	"errors"
+	"fmt"
 	"time"
 
 	pp "github.com/sonalys/pipego"
@@ -10,6 +11,19 @@ import (
 
 const Inf = -1
 
+var (
+	// ErrMaxAttempts is returned when all attempts failed.
+	ErrMaxAttempts = errors.New("retry: maximum attempts reached")
+	// ErrMaxElapsed is returned when the elapsed time budget is exhausted.
+	ErrMaxElapsed = errors.New("retry: maximum elapsed time reached")
+	// ErrBudgetExhausted is returned when the shared retry budget denies a retry.
+	ErrBudgetExhausted = errors.New("retry: budget exhausted")
+	// ErrAttemptTimeout is the cause of cancellation of attempts exceeding their timeout.
+	ErrAttemptTimeout = errors.New("retry: attempt timed out")
+)
+
+// Retrier calculates the delay before the given retry, starting from 0.
+// It can return Stop to stop retrying, which is reported as ErrMaxElapsed.
 type Retrier interface {
 	Retry(retryNumber int) time.Duration
 }
@@ -22,11 +36,16 @@ func (r constantRetry) Retry(n int) time.Duration {
 	return r.Duration
 }
 
+// ConstantDelay returns a Retrier that always returns the same delay.
+func ConstantDelay(delay time.Duration) Retrier {
+	return constantRetry{delay}
+}
+
 // Constant is a constant retry implementation.
 // It always return the same delay.
 // Example: 2s: 2s, 2s, 2s, 2s...
 func Constant(n int, delay time.Duration, steps ...pp.Step) pp.Step {
-	return newRetry(n, constantRetry{delay}, steps...)
+	return With().Constant(n, delay, steps...)
 }
 
 type linearRetry struct {
@@ -37,49 +56,143 @@ func (r linearRetry) Retry(n int) time.Duration {
 	return r.Duration * time.Duration(n)
 }
 
+// LinearDelay returns a Retrier that increases the delay linearly.
+func LinearDelay(delay time.Duration) Retrier {
+	return linearRetry{delay}
+}
+
 // Linear is a linear retry implementation.
 // It returns a linear series for the delay calculation.
 // Example: 1s: 1s, 2s, 3s, 4s, ...
 func Linear(n int, delay time.Duration, steps ...pp.Step) pp.Step {
-	return newRetry(n, linearRetry{delay}, steps...)
-}
-
-type expRetry struct {
-	initial time.Duration
-	max     time.Duration
-	exp     float64
-}
-
-func (r expRetry) Retry(n int) time.Duration {
-	delay := time.Duration(math.Pow(float64(n), r.exp))*time.Second + r.initial
-	if delay > r.max {
-		return r.max
-	}
-	return delay
+	return With().Linear(n, delay, steps...)
 }
 
 // Exp is a exponential retry implementation.
-// Given an initialDelay, it does (initialDelay * n) ^ exp.
-// Example: n ^ 2 + 1s = 1s, 3s, 9s...
+// Given an initialDelay, it does initialDelay * exp ^ n, capped by maxDelay.
+// A maxDelay of 0 means no limit.
+// Example: 1s, 2: 1s, 2s, 4s, 8s...
 func Exp(n int, initialDelay, maxDelay time.Duration, exp float64, steps ...pp.Step) pp.Step {
-	return newRetry(n, expRetry{initialDelay, maxDelay, exp}, steps...)
+	return With().Exp(n, initialDelay, maxDelay, exp, steps...)
 }
 
-// Retry implements a pipeline step for retrying all children steps inside.
+// Retry retries all the given steps using the provided Retrier to calculate delays.
+// It can be used with custom Retrier implementations.
+func Retry(n int, r Retrier, steps ...pp.Step) pp.Step {
+	return With().Retry(n, r, steps...)
+}
+
+// newRetry implements a pipeline step for retrying all children steps inside.
 // If retries = -1, it will retry until it succeeds.
-func newRetry(retries int, r Retrier, steps ...pp.Step) pp.Step {
+// It stops waiting as soon as the context is done, returning the context cause
+// joined with the last attempt's error.
+func newRetry(retries int, r Retrier, cfg config, steps ...pp.Step) pp.Step {
 	return func(ctx context.Context) (err error) {
+		r := fresh(r)
+		// Futures failing inside the retry only release their dependents when it gives up.
+		ctx, giveUp := pp.Retrying(ctx)
+		defer giveUp()
+		start := time.Now()
+		if cfg.maxElapsed > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadlineCause(ctx, start.Add(cfg.maxElapsed), ErrMaxElapsed)
+			defer cancel()
+		}
 		for _, step := range steps {
-			for n := 0; n < retries || retries == -1; n++ {
-				if err = step(ctx); err == nil {
-					break
-				}
-				time.Sleep(r.Retry(n))
+			if err = cfg.run(ctx, start, retries, r, step); err != nil {
+				return err
 			}
-			if err != nil {
+		}
+		return nil
+	}
+}
+
+// run retries a single step until it succeeds, or one of the retry limits is reached.
+func (c config) run(ctx context.Context, start time.Time, retries int, r Retrier, step pp.Step) (err error) {
+	if c.budget != nil {
+		c.budget.deposit()
+	}
+	for n := 0; n < retries || retries == Inf; n++ {
+		if err = c.attempt(context.WithValue(ctx, attemptKey{}, n+1), step); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return interrupted(ctx, err)
+		}
+		// Timed out attempts are always retried, since the step was abandoned by us.
+		if !errors.Is(err, ErrAttemptTimeout) {
+			var retryable bool
+			if retryable, err = c.retryable(err); !retryable {
 				return err
 			}
 		}
+		// There is no point in waiting after the last attempt.
+		if n+1 == retries {
+			return fmt.Errorf("%w: %w", ErrMaxAttempts, err)
+		}
+		delay := c.delay(r, n, err)
+		if delay == Stop || c.maxElapsed > 0 && time.Since(start)+delay > c.maxElapsed {
+			return fmt.Errorf("%w: %w", ErrMaxElapsed, err)
+		}
+		if c.budget != nil && !c.budget.withdraw() {
+			return fmt.Errorf("%w: %w", ErrBudgetExhausted, err)
+		}
+		for _, hook := range c.onRetry {
+			hook(n+1, err, delay)
+		}
+		pp.NotifyRetry(ctx, n+1)
+		if wait(ctx, delay) != nil {
+			return interrupted(ctx, err)
+		}
+	}
+	return err
+}
+
+// attempt runs the step once, abandoning it if the attempt timeout is reached.
+func (c config) attempt(ctx context.Context, step pp.Step) error {
+	if c.attemptTimeout <= 0 {
+		return pp.Execute(ctx, step)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, c.attemptTimeout, ErrAttemptTimeout)
+	defer cancel()
+
+	// The channel is buffered so an abandoned step never blocks.
+	resultCh := make(chan error, 1)
+	go func() {
+		resultCh <- pp.Execute(ctx, step)
+	}()
+
+	select {
+	case err := <-resultCh:
 		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+type attemptKey struct{}
+
+// Attempt returns the current attempt number, starting from 1, for steps running inside a retry.
+// It returns 0 outside of a retry.
+func Attempt(ctx context.Context) int {
+	n, _ := ctx.Value(attemptKey{}).(int)
+	return n
+}
+
+// interrupted returns the context cause joined with the last attempt's error.
+func interrupted(ctx context.Context, err error) error {
+	return fmt.Errorf("retry interrupted: %w", errors.Join(context.Cause(ctx), err))
+}
+
+// wait blocks for the given delay, or until the context is done.
+// It returns the context cause if the wait was interrupted.
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
 	}
 }
@@ -1,9 +1,12 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	pp "github.com/sonalys/pipego"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,16 +24,51 @@ func Test_LinearRetry(t *testing.T) {
 	}
 }
 
-func Test_ExpRetry(t *testing.T) {
-	er := expRetry{time.Second, 10 * time.Second, 2}
-	expSlice := []time.Duration{
-		1 * time.Second,  // 0 ^ 2 + 1 = 1s
-		2 * time.Second,  // 1 ^ 2 + 1 = 2s
-		5 * time.Second,  // 2 ˆ 2 + 1 = 5s
-		10 * time.Second, // 3 ^ 2 + 1 = 10s
-		10 * time.Second, // maxDelay
-	}
-	for i := range expSlice {
-		require.Equal(t, expSlice[i], er.Retry(i))
+func Test_newRetry(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	failing := func(calls *int) pp.Step {
+		return func(_ context.Context) error {
+			*calls++
+			return errMock
+		}
 	}
+	t.Run("no wait after last attempt", func(t *testing.T) {
+		var calls int
+		t1 := time.Now()
+		err := newRetry(1, constantRetry{time.Hour}, config{}, failing(&calls))(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.Equal(t, 1, calls)
+		require.Less(t, time.Since(t1), time.Second)
+	})
+	t.Run("context cancelled while waiting", func(t *testing.T) {
+		var calls int
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err := newRetry(Inf, constantRetry{time.Hour}, config{}, failing(&calls))(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, calls)
+	})
+	t.Run("context cause is preserved", func(t *testing.T) {
+		var calls int
+		errCause := errors.New("shutdown")
+		ctx, cancel := context.WithCancelCause(ctx)
+		cancel(errCause)
+		err := newRetry(3, constantRetry{time.Hour}, config{}, failing(&calls))(ctx)
+		require.ErrorIs(t, err, errCause)
+		require.ErrorIs(t, err, errMock)
+	})
+	t.Run("success after retry", func(t *testing.T) {
+		var calls int
+		err := newRetry(3, constantRetry{time.Millisecond}, config{}, func(_ context.Context) error {
+			calls++
+			if calls < 2 {
+				return errMock
+			}
+			return nil
+		})(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+	})
 }
@@ -0,0 +1,168 @@
+package pp
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// parallelSeq runs the steps from seq like Parallel, pulling them only when there is a free go-routine.
+// If n is 0, there is no limit.
+func parallelSeq(ctx context.Context, n uint16, seq iter.Seq2[int, Step]) error {
+	cfg := getConfig(ctx)
+	path := cfg.metricPath(ctx)
+	errgrp, grpCtx := errgroup.WithContext(ctx)
+	if n > 0 {
+		errgrp.SetLimit(int(n))
+	}
+
+	var interrupted bool
+	for i, step := range seq {
+		if grpCtx.Err() != nil {
+			interrupted = true
+			break
+		}
+		queued := time.Now()
+		errgrp.Go(func() error {
+			cfg.queueWait(path, queued)
+			defer cfg.inFlight(path)()
+			return exec(grpCtx, fmt.Sprintf("parallel[%d]", i), step)
+		})
+	}
+
+	if err := errgrp.Wait(); err != nil || !interrupted {
+		return err
+	}
+	return ctx.Err()
+}
+
+// ForEachSeq takes a sequence `seq` and a stepFactory, and runs a step for each element inside,
+// with 'n' go-routines at a time. Elements are only consumed when there is a free go-routine.
+// It cancels context for the first non-nil error and returns.
+func ForEachSeq[T any](seq iter.Seq[T], n uint16, stepFactory func(T, int) Step) Step {
+	return func(ctx context.Context) error {
+		return parallelSeq(ctx, n, func(yield func(int, Step) bool) {
+			i := 0
+			for v := range seq {
+				if !yield(i, stepFactory(v, i)) {
+					return
+				}
+				i++
+			}
+		})
+	}
+}
+
+// ForEachSeq2 works like ForEachSeq, for sequences of pairs like maps.All or database rows with their errors.
+func ForEachSeq2[K, V any](seq iter.Seq2[K, V], n uint16, stepFactory func(K, V) Step) Step {
+	return func(ctx context.Context) error {
+		return parallelSeq(ctx, n, func(yield func(int, Step) bool) {
+			i := 0
+			for k, v := range seq {
+				if !yield(i, stepFactory(k, v)) {
+					return
+				}
+				i++
+			}
+		})
+	}
+}
+
+// DivideSeqInSize receives a sequence `seq` and divide it into groups with `size` elements each,
+// then it uses a step factory to generate steps for each group, running 'n' groups at a time.
+// Groups are only consumed when there is a free go-routine.
+// `size` must be greater than 0 or it will panic.
+func DivideSeqInSize[T any](seq iter.Seq[T], size int, n uint16, stepFactory func(T) Step) Step {
+	if size < 1 {
+		panic("cannot be less than 1")
+	}
+	return func(ctx context.Context) error {
+		return parallelSeq(ctx, n, func(yield func(int, Step) bool) {
+			i := 0
+			batch := make(Steps, 0, size)
+			for v := range seq {
+				if batch = append(batch, stepFactory(v)); len(batch) < size {
+					continue
+				}
+				if !yield(i, batch.Group()) {
+					return
+				}
+				i++
+				batch = make(Steps, 0, size)
+			}
+			if len(batch) > 0 {
+				yield(i, batch.Group())
+			}
+		})
+	}
+}
+
+type mapResult[R any] struct {
+	value R
+	err   error
+}
+
+// MapSeq calls fn for each element of `seq` in parallel, running 'n' go-routines at a time,
+// and returns a sequence of the results and errors in the same order as the input.
+// Elements are pulled lazily by the go-routine iterating the results, at most 'n' of them ahead.
+// Failed elements don't stop the sequence, stop iterating to cancel the remaining ones.
+// When the iteration ends, `seq` and all calls to fn have returned.
+// `n` must be greater than 0 or it will panic.
+func MapSeq[T, R any](ctx context.Context, seq iter.Seq[T], n uint16, fn MapFunc[T, R]) iter.Seq2[R, error] {
+	if n < 1 {
+		panic("cannot be less than 1")
+	}
+	return func(yield func(R, error) bool) {
+		next, stop := iter.Pull(seq)
+		defer stop()
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		cfg := getConfig(ctx)
+		path := cfg.metricPath(ctx)
+
+		// pending holds the result channel of each started element, in input order.
+		var pending []chan mapResult[R]
+		i, more := 0, true
+		for {
+			for more && len(pending) < int(n) && ctx.Err() == nil {
+				var v T
+				if v, more = next(); !more {
+					break
+				}
+				result := make(chan mapResult[R], 1)
+				pending = append(pending, result)
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					defer cfg.inFlight(path)()
+					var r mapResult[R]
+					r.err = exec(ctx, fmt.Sprintf("parallel[%d]", i), func(ctx context.Context) (err error) {
+						r.value, err = fn(ctx, v)
+						return err
+					})
+					result <- r
+				}(i)
+				i++
+			}
+			if len(pending) == 0 {
+				break
+			}
+			r := <-pending[0]
+			pending = pending[1:]
+			if !yield(r.value, r.err) {
+				return
+			}
+		}
+		// The sequence was interrupted by the context.
+		if more {
+			var zero R
+			yield(zero, context.Cause(ctx))
+		}
+	}
+}
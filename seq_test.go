@@ -0,0 +1,199 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSeq yields the numbers from 0 to n-1, tracking the maximum of them pulled but not yet finished.
+type countingSeq struct {
+	pulled, finished, peak atomic.Int32
+}
+
+func (c *countingSeq) seq(n int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := range n {
+			cur := c.pulled.Add(1) - c.finished.Load()
+			for {
+				old := c.peak.Load()
+				if cur <= old || c.peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (c *countingSeq) done() {
+	time.Sleep(time.Millisecond)
+	c.finished.Add(1)
+}
+
+func Test_ForEachSeq(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("bounded concurrency", func(t *testing.T) {
+		var c countingSeq
+		var sum atomic.Int32
+		err := pp.ForEachSeq(c.seq(20), 3, func(v, i int) pp.Step {
+			return func(_ context.Context) error {
+				defer c.done()
+				require.Equal(t, v, i)
+				sum.Add(int32(v))
+				return nil
+			}
+		})(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 190, sum.Load())
+		require.LessOrEqual(t, c.peak.Load(), int32(4))
+	})
+	t.Run("stops consuming on error", func(t *testing.T) {
+		var c countingSeq
+		err := pp.ForEachSeq(c.seq(1000), 1, func(v, _ int) pp.Step {
+			return func(_ context.Context) error {
+				if v == 2 {
+					return errMock
+				}
+				return nil
+			}
+		})(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.Less(t, c.pulled.Load(), int32(10))
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := pp.ForEachSeq(slices.Values([]int{1}), 1, func(_, _ int) pp.Step {
+			return func(_ context.Context) error { return nil }
+		})(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("pairs", func(t *testing.T) {
+		var mu sync.Mutex
+		got := map[string]int{}
+		in := map[string]int{"a": 1, "b": 2}
+		err := pp.ForEachSeq2(maps.All(in), 0, func(k string, v int) pp.Step {
+			return func(_ context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				got[k] = v
+				return nil
+			}
+		})(ctx)
+		require.NoError(t, err)
+		require.Equal(t, in, got)
+	})
+}
+
+func Test_DivideSeqInSize(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var groups [][]int
+	err := pp.DivideSeqInSize(slices.Values([]int{1, 2, 3, 4, 5}), 2, 1, func(v int) pp.Step {
+		return func(_ context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if v%2 == 1 {
+				groups = append(groups, nil)
+			}
+			groups[len(groups)-1] = append(groups[len(groups)-1], v)
+			return nil
+		}
+	})(ctx)
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, groups)
+	require.Panics(t, func() { pp.DivideSeqInSize(slices.Values([]int{}), 0, 1, nil) })
+}
+
+func Test_MapSeq(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("ordered and bounded", func(t *testing.T) {
+		var c countingSeq
+		var got []int
+		for v, err := range pp.MapSeq(ctx, c.seq(20), 3, func(_ context.Context, v int) (int, error) {
+			defer c.done()
+			// Inverts the completion order, to check the results keep the input order.
+			time.Sleep(time.Duration(3-v%3) * time.Millisecond)
+			return v * 2, nil
+		}) {
+			require.NoError(t, err)
+			got = append(got, v)
+		}
+		require.Len(t, got, 20)
+		for i, v := range got {
+			require.Equal(t, i*2, v)
+		}
+		require.LessOrEqual(t, c.peak.Load(), int32(4))
+	})
+	t.Run("errors don't stop the sequence", func(t *testing.T) {
+		var errs int
+		for _, err := range pp.MapSeq(ctx, slices.Values([]int{1, 2, 3}), 2, func(_ context.Context, v int) (int, error) {
+			if v == 2 {
+				return 0, errMock
+			}
+			return v, nil
+		}) {
+			if err != nil {
+				require.ErrorIs(t, err, errMock)
+				errs++
+			}
+		}
+		require.Equal(t, 1, errs)
+	})
+	t.Run("break cancels the remaining", func(t *testing.T) {
+		var c countingSeq
+		for v, err := range pp.MapSeq(ctx, c.seq(1000), 2, func(_ context.Context, v int) (int, error) {
+			return v, nil
+		}) {
+			require.NoError(t, err)
+			if v == 5 {
+				break
+			}
+		}
+		require.Less(t, c.pulled.Load(), int32(1000))
+	})
+	t.Run("sequence returned after break", func(t *testing.T) {
+		var inside atomic.Bool
+		seq := func(yield func(int) bool) {
+			inside.Store(true)
+			defer inside.Store(false)
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		for v := range pp.MapSeq(ctx, seq, 4, func(_ context.Context, v int) (int, error) {
+			return v, nil
+		}) {
+			if v == 10 {
+				break
+			}
+		}
+		require.False(t, inside.Load())
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		var last error
+		for _, err := range pp.MapSeq(ctx, slices.Values([]int{1, 2}), 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		}) {
+			last = err
+		}
+		require.ErrorIs(t, last, context.Canceled)
+	})
+}
@@ -1,6 +1,7 @@
 package pp
 
 import (
+	"context"
 	"slices"
 )
 
@@ -57,3 +58,58 @@ func ForEach[T any](s []T, stepFactory func(T, int) Step) Step {
 	}
 	return batch.Group()
 }
+
+// MapFunc defines a function signature to transform a value into a result.
+type MapFunc[T, R any] func(context.Context, T) (R, error)
+
+// mapSteps creates a step for each element of `in`, writing its result in the same index of `out`.
+func mapSteps[T, R any](out []R, in []T, fn MapFunc[T, R]) Steps {
+	steps := make(Steps, len(in))
+	for i, v := range in {
+		steps[i] = func(ctx context.Context) (err error) {
+			out[i], err = fn(ctx, v)
+			return err
+		}
+	}
+	return steps
+}
+
+// Map calls fn for each element of `in` in parallel, like Parallel, running 'n' go-routines at a time.
+// If n is 0, there is no limit.
+// It returns the results in the same order as the input, or the first non-nil error.
+func Map[T, R any](ctx context.Context, in []T, n uint16, fn MapFunc[T, R]) ([]R, error) {
+	out := make([]R, len(in))
+	if err := Parallel(n, mapSteps(out, in, fn)...)(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MapAll works like Map, but calls fn for all elements even if some of them fail, like ParallelAll.
+// It returns all the results, with zero values for the failed elements,
+// and all errors joined, each one as an *IndexedError.
+func MapAll[T, R any](ctx context.Context, in []T, n uint16, fn MapFunc[T, R]) ([]R, error) {
+	out := make([]R, len(in))
+	err := ParallelAll(n, mapSteps(out, in, fn)...)(ctx)
+	return out, err
+}
+
+// MapInto creates a step running Map, and storing its results into dst when it succeeds.
+func MapInto[T, R any](dst *[]R, in []T, n uint16, fn MapFunc[T, R]) Step {
+	return func(ctx context.Context) (err error) {
+		out, err := Map(ctx, in, n, fn)
+		if err != nil {
+			return err
+		}
+		*dst = out
+		return nil
+	}
+}
+
+// MapAllInto creates a step running MapAll, and storing its results into dst.
+func MapAllInto[T, R any](dst *[]R, in []T, n uint16, fn MapFunc[T, R]) Step {
+	return func(ctx context.Context) (err error) {
+		*dst, err = MapAll(ctx, in, n, fn)
+		return err
+	}
+}
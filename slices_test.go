@@ -2,7 +2,10 @@ package pp
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -78,3 +81,72 @@ func TestDivideSliceInGroups(t *testing.T) {
 		require.Equal(t, 4, *count)
 	})
 }
+
+func TestMap(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	double := func(_ context.Context, v int) (int, error) {
+		// Inverts the completion order, to check the results keep the input order.
+		time.Sleep(time.Duration(10-v) * time.Millisecond)
+		return v * 2, nil
+	}
+	failOdd := func(_ context.Context, v int) (string, error) {
+		if v%2 == 1 {
+			return "", errMock
+		}
+		return strconv.Itoa(v), nil
+	}
+	t.Run("empty", func(t *testing.T) {
+		out, err := Map(ctx, []int{}, 0, double)
+		require.NoError(t, err)
+		require.Empty(t, out)
+	})
+	t.Run("ordered results", func(t *testing.T) {
+		out, err := Map(ctx, []int{1, 2, 3, 4, 5}, 2, double)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4, 6, 8, 10}, out)
+	})
+	t.Run("no limit for inputs over uint16", func(t *testing.T) {
+		out, err := Map(ctx, make([]int, 1<<16+1), 0, func(_ context.Context, v int) (int, error) {
+			return v + 1, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, out, 1<<16+1)
+		require.Equal(t, 1, out[1<<16])
+		out, err = MapAll(ctx, make([]int, 1<<16+1), 0, func(_ context.Context, v int) (int, error) {
+			return v + 1, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, out[1<<16])
+	})
+	t.Run("first error", func(t *testing.T) {
+		out, err := Map(ctx, []int{0, 1, 2}, 1, failOdd)
+		require.ErrorIs(t, err, errMock)
+		require.Nil(t, out)
+	})
+	t.Run("all errors", func(t *testing.T) {
+		out, err := MapAll(ctx, []int{0, 1, 2, 3}, 0, failOdd)
+		require.Equal(t, []string{"0", "", "2", ""}, out)
+		var indexed *IndexedError
+		require.ErrorAs(t, err, &indexed)
+		require.Equal(t, 1, indexed.Index)
+		require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+	})
+	t.Run("into destination", func(t *testing.T) {
+		var doubled []int
+		var strs []string
+		err := Run(ctx,
+			MapInto(&doubled, []int{1, 2, 3}, 0, double),
+			MapAllInto(&strs, []int{0, 2}, 0, failOdd),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4, 6}, doubled)
+		require.Equal(t, []string{"0", "2"}, strs)
+	})
+	t.Run("destination unset on error", func(t *testing.T) {
+		var strs []string
+		err := MapInto(&strs, []int{1}, 0, failOdd)(ctx)
+		require.ErrorIs(t, err, errMock)
+		require.Nil(t, strs)
+	})
+}
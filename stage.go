@@ -0,0 +1,127 @@
+package pp
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Stage creates a streaming stage, transforming the values from `in` with `workers` go-routines,
+// and returns its output channel, buffered with `buffer` elements, with the step running it.
+// The output channel is closed when `in` is closed and all values were processed, or when the stage fails.
+// The first non-nil error cancels the other workers.
+// The step must run only once, in parallel with the steps producing and consuming its channels, like in:
+//
+//	decoded, decode := pp.Stage(lines, 4, 16, decodeLine)
+//	enriched, enrich := pp.Stage(decoded, 8, 16, enrichRecord)
+//	err := pp.Run(ctx, pp.Parallel(0, decode, enrich, pp.ChanDivide(&enriched, writeRecord)))
+//
+// `workers` must be greater than 0 or it will panic.
+func Stage[In, Out any](in <-chan In, workers uint16, buffer int, fn MapFunc[In, Out]) (<-chan Out, Step) {
+	if workers < 1 {
+		panic("cannot be less than 1")
+	}
+	out := make(chan Out, buffer)
+	return out, func(ctx context.Context) error {
+		defer close(out)
+		cfg := getConfig(ctx)
+		path := cfg.metricPath(ctx)
+		errgrp, ctx := errgroup.WithContext(ctx)
+		for i := range int(workers) {
+			errgrp.Go(func() error {
+				for {
+					select {
+					case v, ok := <-in:
+						if !ok {
+							return nil
+						}
+						var r Out
+						done := cfg.inFlight(path)
+						err := exec(ctx, fmt.Sprintf("worker[%d]", i), func(ctx context.Context) (err error) {
+							r, err = fn(ctx, v)
+							return err
+						})
+						done()
+						if err != nil {
+							return err
+						}
+						if err := send(ctx, r, out); err != nil {
+							return err
+						}
+					case <-ctx.Done():
+						return context.Cause(ctx)
+					}
+				}
+			})
+		}
+		return errgrp.Wait()
+	}
+}
+
+// Merge creates a fan-in stage, forwarding the values from all the given channels into a single one,
+// buffered with `buffer` elements, and returns it with the step running it.
+// The output channel is closed when all the inputs are closed, or the context is done.
+func Merge[T any](buffer int, ins ...<-chan T) (<-chan T, Step) {
+	out := make(chan T, buffer)
+	return out, func(ctx context.Context) error {
+		defer close(out)
+		errgrp, ctx := errgroup.WithContext(ctx)
+		for _, in := range ins {
+			errgrp.Go(func() error {
+				return forward(ctx, in, out)
+			})
+		}
+		return errgrp.Wait()
+	}
+}
+
+// Tee creates a fan-out stage, copying each value from `in` into `n` channels,
+// buffered with `buffer` elements, and returns them with the step running it.
+// Each value is sent to all the outputs before the next is read, so the slowest consumer sets the pace.
+// The output channels are closed when `in` is closed, or the context is done.
+func Tee[T any](in <-chan T, n int, buffer int) ([]<-chan T, Step) {
+	outs := make([]chan T, n)
+	views := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T, buffer)
+		views[i] = outs[i]
+	}
+	return views, func(ctx context.Context) error {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		return forward(ctx, in, outs...)
+	}
+}
+
+// forward sends all values from `in` to all `outs`, until `in` is closed or the context is done.
+func forward[T any](ctx context.Context, in <-chan T, outs ...chan T) error {
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return nil
+			}
+			for _, out := range outs {
+				if err := send(ctx, v, out); err != nil {
+					return err
+				}
+			}
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// send writes v into out, unless the context is done first.
+func send[T any](ctx context.Context, v T, out chan T) error {
+	select {
+	case out <- v:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,111 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/stretchr/testify/require"
+)
+
+// source returns a closed channel holding the given values.
+func source[T any](values ...T) <-chan T {
+	ch := make(chan T, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+// collect creates a step reading all values from ch.
+func collect[T any](ch <-chan T, dst *[]T) pp.Step {
+	return func(ctx context.Context) error {
+		for v := range ch {
+			*dst = append(*dst, v)
+		}
+		return nil
+	}
+}
+
+func Test_Stage(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("mock")
+	t.Run("multiple stages", func(t *testing.T) {
+		decoded, decode := pp.Stage(source("1", "2", "3", "4"), 2, 1, func(_ context.Context, s string) (int, error) {
+			return strconv.Atoi(s)
+		})
+		squared, square := pp.Stage(decoded, 3, 0, func(_ context.Context, v int) (int, error) {
+			return v * v, nil
+		})
+		var got []int
+		err := pp.Run(ctx, pp.Parallel(0, decode, square, collect(squared, &got)))
+		require.NoError(t, err)
+		slices.Sort(got)
+		require.Equal(t, []int{1, 4, 9, 16}, got)
+	})
+	t.Run("error cancels all stages", func(t *testing.T) {
+		// The input is never closed, so the stages only end through cancellation.
+		in := make(chan string, 2)
+		in <- "1"
+		in <- "2"
+		decoded, decode := pp.Stage(in, 1, 0, func(_ context.Context, s string) (int, error) {
+			return strconv.Atoi(s)
+		})
+		failed, fail := pp.Stage(decoded, 1, 0, func(_ context.Context, v int) (int, error) {
+			if v == 1 {
+				return 0, errMock
+			}
+			return v, nil
+		})
+		var got []int
+		err := pp.Run(ctx, pp.Parallel(0, decode, fail, collect(failed, &got)))
+		require.ErrorIs(t, err, errMock)
+		require.NotContains(t, got, 1)
+	})
+	t.Run("panics with no workers", func(t *testing.T) {
+		require.Panics(t, func() {
+			pp.Stage(source(1), 0, 0, func(_ context.Context, v int) (int, error) { return v, nil })
+		})
+	})
+}
+
+func Test_Merge(t *testing.T) {
+	ctx := context.Background()
+	merged, merge := pp.Merge(0, source(1, 2), source(3), source[int]())
+	var got []int
+	err := pp.Run(ctx, pp.Parallel(0, merge, collect(merged, &got)))
+	require.NoError(t, err)
+	slices.Sort(got)
+	require.Equal(t, []int{1, 2, 3}, got)
+}
+
+func Test_Tee(t *testing.T) {
+	ctx := context.Background()
+	t.Run("copies to all outputs", func(t *testing.T) {
+		outs, tee := pp.Tee(source(1, 2, 3), 2, 0)
+		var a, b []int
+		err := pp.Run(ctx, pp.Parallel(0, tee, collect(outs[0], &a), collect(outs[1], &b)))
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2, 3}, a)
+		require.Equal(t, []int{1, 2, 3}, b)
+	})
+	t.Run("closes outputs on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		outs, tee := pp.Tee(make(chan int), 1, 0)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range outs[0] {
+			}
+		}()
+		cancel()
+		require.ErrorIs(t, tee(ctx), context.Canceled)
+		wg.Wait()
+	})
+}
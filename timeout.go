@@ -18,11 +18,11 @@ func Timeout(d time.Duration, steps ...Step) (out Steps) {
 			ctx, cancel := context.WithCancelCause(ctx)
 			defer cancel(context.DeadlineExceeded)
 
+			// The channel is buffered so the step routine never blocks when the timer wins.
 			resultCh := make(chan error, 1)
-			defer close(resultCh)
 
 			go func() {
-				resultCh <- step(ctx)
+				resultCh <- exec(ctx, "", step)
 			}()
 
 			select {
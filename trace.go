@@ -0,0 +1,43 @@
+package pp
+
+import "context"
+
+type (
+	// Tracer starts spans for steps.
+	// It's a small interface, so pipego doesn't depend on any tracing library,
+	// see the otel module for an OpenTelemetry adapter.
+	Tracer interface {
+		// Start starts a span as a child of the span in ctx, if any.
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+
+	// Span is a traced step execution.
+	Span interface {
+		// End ends the span, with the error returned by the step.
+		End(err error)
+	}
+)
+
+// WithTracer traces every step executed under the context,
+// including parallel branches, ChanDivide jobs and retry attempts.
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// traced starts a span for each step execution, named after the step.
+func traced(t Tracer, step Step) Step {
+	return func(ctx context.Context) (err error) {
+		ctx, span := t.Start(ctx, spanName(ctx))
+		defer func() { span.End(err) }()
+		return step(ctx)
+	}
+}
+
+func spanName(ctx context.Context) string {
+	if name := stepName(ctx); name != "" {
+		return name
+	}
+	return "pipeline"
+}
@@ -0,0 +1,64 @@
+package pp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pp "github.com/sonalys/pipego"
+	"github.com/sonalys/pipego/retry"
+	"github.com/sonalys/pipego/tracetest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithTracer(t *testing.T) {
+	var recorder tracetest.Recorder
+	ctx := pp.WithOptions(context.Background(), pp.WithTracer(&recorder))
+	errMock := errors.New("mock")
+	var attempts int
+
+	ch := make(chan int, 2)
+	var recv <-chan int = ch
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	err := pp.Run(ctx,
+		pp.Named("checkout", pp.Parallel(2,
+			func(_ context.Context) error { return nil },
+			pp.Named("fetch", retry.Constant(2, time.Millisecond, pp.Named("call", func(_ context.Context) error {
+				if attempts++; attempts == 1 {
+					return errMock
+				}
+				return nil
+			}))),
+		)),
+		pp.Named("consume", pp.ChanDivide(&recv, func(_ context.Context, _ int) error { return nil })),
+	)
+	require.NoError(t, err)
+
+	type span struct {
+		name, path, parent string
+		err                error
+	}
+	var spans []span
+	for _, s := range recorder.Spans() {
+		require.True(t, s.Ended)
+		var parent string
+		if s.Parent != nil {
+			parent = s.Parent.Name
+		}
+		spans = append(spans, span{s.Name, s.Path, parent, s.Err})
+	}
+	require.ElementsMatch(t, []span{
+		{"checkout", "checkout", "", nil},
+		{"parallel[0]", "checkout/parallel[0]", "checkout", nil},
+		{"fetch", "checkout/parallel[1]/fetch", "checkout", nil},
+		{"call", "checkout/parallel[1]/fetch/call", "fetch", errMock},
+		{"call", "checkout/parallel[1]/fetch/call", "fetch", nil},
+		{"consume", "consume", "", nil},
+		{"worker[0]", "consume/worker[0]", "consume", nil},
+		{"worker[0]", "consume/worker[0]", "consume", nil},
+	}, spans)
+}
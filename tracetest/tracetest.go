@@ -0,0 +1,59 @@
+// Package tracetest implements an in-memory pp.Tracer for tests.
+package tracetest
+
+import (
+	"context"
+	"sync"
+
+	pp "github.com/sonalys/pipego"
+)
+
+// Span is a recorded span.
+type Span struct {
+	Name   string
+	Path   string
+	Parent *Span
+	Err    error
+	Ended  bool
+}
+
+// Recorder is an in-memory pp.Tracer, recording all spans.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+type spanKey struct{}
+
+type span struct {
+	recorder *Recorder
+	span     *Span
+}
+
+func (s span) End(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.span.Err = err
+	s.span.Ended = true
+}
+
+// Start implements pp.Tracer.
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, pp.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*Span)
+	s := &Span{Name: name, Path: pp.Path(ctx), Parent: parent}
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), span{r, s}
+}
+
+// Spans returns all recorded spans, in the order they were started.
+func (r *Recorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spans := make([]Span, 0, len(r.spans))
+	for _, s := range r.spans {
+		spans = append(spans, *s)
+	}
+	return spans
+}